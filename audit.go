@@ -0,0 +1,434 @@
+package twitch
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultAuditMaxEntries  = 10000
+	DefaultAuditDedupWindow = 2 * time.Second
+)
+
+// AuditAction is the action of an AuditEntry. Entries from channel.moderate
+// use the ModerateAction without the shared chat prefix, like "vip" or
+// "slow", the other events use the constants below.
+type AuditAction string
+
+const (
+	AuditActionBan                  AuditAction = "ban"
+	AuditActionTimeout              AuditAction = "timeout"
+	AuditActionUnban                AuditAction = "unban"
+	AuditActionUntimeout            AuditAction = "untimeout"
+	AuditActionWarn                 AuditAction = "warn"
+	AuditActionDelete               AuditAction = "delete"
+	AuditActionClear                AuditAction = "clear"
+	AuditActionClearUserMessages    AuditAction = "clear_user_messages"
+	AuditActionWarningAcknowledge   AuditAction = "warning_acknowledge"
+	AuditActionUnbanRequestCreate   AuditAction = "unban_request_create"
+	AuditActionApproveUnbanRequest  AuditAction = "approve_unban_request"
+	AuditActionDenyUnbanRequest     AuditAction = "deny_unban_request"
+	AuditActionCancelUnbanRequest   AuditAction = "cancel_unban_request"
+	AuditActionAutomodApprove       AuditAction = "automod_approve"
+	AuditActionAutomodDeny          AuditAction = "automod_deny"
+	AuditActionAutomodExpire        AuditAction = "automod_expire"
+	AuditActionSuspiciousUserUpdate AuditAction = "suspicious_user_update"
+)
+
+// AuditEntry is a moderation action normalized from one of the events
+// handled by AuditLog. Actor is the moderator, or the user for actions users
+// take themselves like acknowledging a warning. DurationSeconds is the
+// length of a timeout or temporary ban, or the follow time or slow mode
+// delay set by the action.
+type AuditEntry struct {
+	ID              string            `json:"id"`
+	Event           EventSubscription `json:"event"`
+	Action          AuditAction       `json:"action"`
+	Channel         Broadcaster       `json:"channel"`
+	SourceChannel   Broadcaster       `json:"source_channel"`
+	IsSharedChat    bool              `json:"is_shared_chat"`
+	Actor           User              `json:"actor"`
+	Target          User              `json:"target"`
+	Reason          string            `json:"reason,omitempty"`
+	DurationSeconds int               `json:"duration_seconds,omitempty"`
+	MessageID       string            `json:"message_id,omitempty"`
+	Message         string            `json:"message,omitempty"`
+	Details         string            `json:"details,omitempty"`
+	At              time.Time         `json:"at"`
+}
+
+type AuditSink interface {
+	WriteAudit(entry AuditEntry) error
+}
+
+type AuditSinkFunc func(entry AuditEntry) error
+
+func (f AuditSinkFunc) WriteAudit(entry AuditEntry) error {
+	return f(entry)
+}
+
+type jsonAuditSink struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+}
+
+// NewJSONAuditSink writes every entry as a line of json.
+func NewJSONAuditSink(w io.Writer) AuditSink {
+	return &jsonAuditSink{encoder: json.NewEncoder(w)}
+}
+
+func (s *jsonAuditSink) WriteAudit(entry AuditEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.encoder.Encode(entry)
+}
+
+// AuditFilter selects entries in AuditLog.Entries. Empty fields match
+// every entry.
+type AuditFilter struct {
+	ChannelID string
+	ActorID   string
+	TargetID  string
+	Actions   []AuditAction
+	Since     time.Time
+	Until     time.Time
+	// Limit keeps the newest entries when set.
+	Limit int
+}
+
+func (f AuditFilter) match(entry AuditEntry) bool {
+	switch {
+	case f.ChannelID != "" && entry.Channel.BroadcasterUserId != f.ChannelID:
+		return false
+	case f.ActorID != "" && entry.Actor.UserID != f.ActorID:
+		return false
+	case f.TargetID != "" && entry.Target.UserID != f.TargetID:
+		return false
+	case !f.Since.IsZero() && entry.At.Before(f.Since):
+		return false
+	case !f.Until.IsZero() && entry.At.After(f.Until):
+		return false
+	}
+
+	if len(f.Actions) == 0 {
+		return true
+	}
+	for _, action := range f.Actions {
+		if entry.Action == action {
+			return true
+		}
+	}
+	return false
+}
+
+// AuditLog turns moderation events into AuditEntry values, writes them to
+// the sinks and keeps the newest MaxEntries in memory for queries. The same
+// action reported by two events, like channel.ban and channel.moderate, is
+// logged once when they arrive within DedupWindow. Chat events don't know
+// the moderator, so they match an entry of any actor.
+type AuditLog struct {
+	MaxEntries  int
+	DedupWindow time.Duration
+
+	notifier
+
+	entries []AuditEntry
+	sinks   []AuditSink
+	onError func(err error)
+}
+
+func NewAuditLog(sinks ...AuditSink) *AuditLog {
+	return &AuditLog{
+		MaxEntries:  DefaultAuditMaxEntries,
+		DedupWindow: DefaultAuditDedupWindow,
+		sinks:       sinks,
+		onError:     func(err error) { fmt.Printf("ERROR: %v\n", err) },
+	}
+}
+
+func (l *AuditLog) AddSink(sink AuditSink) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sinks = append(l.sinks, sink)
+}
+
+func (l *AuditLog) OnError(callback func(err error)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.onError = callback
+}
+
+// Entries returns the entries matching the filter, oldest first.
+func (l *AuditLog) Entries(filter AuditFilter) []AuditEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var entries []AuditEntry
+	for _, entry := range l.entries {
+		if filter.match(entry) {
+			entries = append(entries, entry)
+		}
+	}
+
+	if filter.Limit > 0 && len(entries) > filter.Limit {
+		entries = entries[len(entries)-filter.Limit:]
+	}
+	return entries
+}
+
+// History returns the entries targeting the user, oldest first.
+func (l *AuditLog) History(userID string) []AuditEntry {
+	return l.Entries(AuditFilter{TargetID: userID})
+}
+
+// Actions returns the entries of actions taken by the moderator, oldest
+// first.
+func (l *AuditLog) Actions(moderatorID string) []AuditEntry {
+	return l.Entries(AuditFilter{ActorID: moderatorID})
+}
+
+// Add logs an entry. It's called by the handlers and can be used to log
+// actions from other sources.
+func (l *AuditLog) Add(entry AuditEntry) {
+	if entry.SourceChannel.BroadcasterUserId == "" {
+		entry.SourceChannel = entry.Channel
+	}
+
+	l.mu.Lock()
+	if l.isDuplicate(entry) {
+		l.mu.Unlock()
+		return
+	}
+
+	i := len(l.entries)
+	for i > 0 && entry.At.Before(l.entries[i-1].At) {
+		i--
+	}
+	l.entries = append(l.entries, AuditEntry{})
+	copy(l.entries[i+1:], l.entries[i:])
+	l.entries[i] = entry
+
+	if l.MaxEntries > 0 && len(l.entries) > l.MaxEntries {
+		l.entries = append([]AuditEntry(nil), l.entries[len(l.entries)-l.MaxEntries:]...)
+	}
+
+	sinks, onError := l.sinks, l.onError
+	l.unlockAndNotify(func() {
+		for _, sink := range sinks {
+			err := sink.WriteAudit(entry)
+			if err != nil && onError != nil {
+				onError(fmt.Errorf("could not write audit entry %s: %w", entry.ID, err))
+			}
+		}
+	})
+}
+
+func (l *AuditLog) isDuplicate(entry AuditEntry) bool {
+	for i := len(l.entries) - 1; i >= 0; i-- {
+		seen := l.entries[i]
+		if seen.ID == entry.ID && entry.ID != "" {
+			return true
+		}
+
+		gap := entry.At.Sub(seen.At)
+		if gap < 0 {
+			gap = -gap
+		}
+		if gap > l.DedupWindow {
+			if seen.At.Before(entry.At) {
+				return false
+			}
+			continue
+		}
+
+		if seen.Action == entry.Action && seen.Event != entry.Event &&
+			seen.Channel.BroadcasterUserId == entry.Channel.BroadcasterUserId &&
+			seen.Target.UserID == entry.Target.UserID &&
+			(seen.Actor.UserID == entry.Actor.UserID || seen.Actor.UserID == "" || entry.Actor.UserID == "") {
+			return true
+		}
+	}
+	return false
+}
+
+func newAuditEntry(msg NotificationMessage, action AuditAction, channel Broadcaster) AuditEntry {
+	return AuditEntry{
+		ID:      msg.Metadata.MessageID,
+		Event:   msg.Payload.Subscription.Type,
+		Action:  action,
+		Channel: channel,
+		At:      msg.Metadata.MessageTimestamp,
+	}
+}
+
+func moderatorUser(moderator Moderator) User {
+	return User{
+		UserID:    moderator.ModeratorUserId,
+		UserLogin: moderator.ModeratorUserLogin,
+		UserName:  moderator.ModeratorUserName,
+	}
+}
+
+func targetUser(target Target) User {
+	return User{
+		UserID:    target.TargetUserId,
+		UserLogin: target.TargetUserLogin,
+		UserName:  target.TargetUserName,
+	}
+}
+
+func (l *AuditLog) HandleModerate(event EventChannelModerate, msg NotificationMessage) {
+	entry := newAuditEntry(msg, AuditAction(event.Action.Base()), event.Broadcaster)
+	entry.Actor = moderatorUser(event.Moderator)
+	entry.IsSharedChat = event.Action.IsSharedChat()
+	entry.SourceChannel = Broadcaster{
+		BroadcasterUserId:    event.SourceBroadcasterUserId,
+		BroadcasterUserLogin: event.SourceBroadcasterUserLogin,
+		BroadcasterUserName:  event.SourceBroadcasterUserName,
+	}
+	entry.Target, _ = event.TargetUser()
+
+	switch detail := event.Detail().(type) {
+	case ModerateBan:
+		entry.Reason = detail.Reason
+	case ModerateTimeout:
+		entry.Reason = detail.Reason
+		if !detail.ExpiresAt.IsZero() && !entry.At.IsZero() {
+			entry.DurationSeconds = int(detail.ExpiresAt.Sub(entry.At).Round(time.Second).Seconds())
+		}
+	case ModerateDelete:
+		entry.MessageID = detail.MessageId
+		entry.Message = detail.MessageBody
+	case ModerateRaid:
+		entry.Details = fmt.Sprintf("%d viewers", detail.ViewerCount)
+	case ModerateFollowers:
+		entry.DurationSeconds = detail.FollowDurationMinutes * 60
+	case ModerateSlow:
+		entry.DurationSeconds = detail.WaitTimeSeconds
+	case ModerateWarn:
+		entry.Reason = detail.Reason
+		entry.Details = strings.Join(detail.ChatRulesCited, ", ")
+	case ModerateAutomodTerms:
+		entry.Details = strings.Join(detail.Terms, ", ")
+	case ModerateUnbanRequest:
+		entry.Reason = detail.ModeratorMessage
+	}
+
+	l.Add(entry)
+}
+
+func (l *AuditLog) HandleBan(event EventChannelBan, msg NotificationMessage) {
+	action := AuditActionBan
+	if !event.IsPermanent {
+		action = AuditActionTimeout
+	}
+
+	entry := newAuditEntry(msg, action, event.Broadcaster)
+	entry.Actor = moderatorUser(event.Moderator)
+	entry.Target = event.User
+	entry.Reason = event.Reason
+	if event.EndsAt != nil {
+		entry.DurationSeconds = int(event.EndsAt.Sub(event.BannedAt).Round(time.Second).Seconds())
+	}
+	l.Add(entry)
+}
+
+func (l *AuditLog) HandleUnban(event EventChannelUnban, msg NotificationMessage) {
+	entry := newAuditEntry(msg, AuditActionUnban, event.Broadcaster)
+	entry.Actor = moderatorUser(event.Moderator)
+	entry.Target = event.User
+	l.Add(entry)
+}
+
+func (l *AuditLog) HandleWarningSend(event EventChannelWarningSend, msg NotificationMessage) {
+	entry := newAuditEntry(msg, AuditActionWarn, event.Broadcaster)
+	entry.Actor = moderatorUser(event.Moderator)
+	entry.Target = event.User
+	entry.Reason = event.Reason
+	entry.Details = strings.Join(event.ChatRulesCited, ", ")
+	l.Add(entry)
+}
+
+func (l *AuditLog) HandleWarningAcknowledge(event EventChannelWarningAcknowledge, msg NotificationMessage) {
+	entry := newAuditEntry(msg, AuditActionWarningAcknowledge, event.Broadcaster)
+	entry.Actor = event.User
+	entry.Target = event.User
+	l.Add(entry)
+}
+
+func (l *AuditLog) HandleUnbanRequestCreate(event EventChannelUnbanRequestCreate, msg NotificationMessage) {
+	entry := newAuditEntry(msg, AuditActionUnbanRequestCreate, event.Broadcaster)
+	entry.Actor = event.User
+	entry.Target = event.User
+	entry.Message = event.Text
+	entry.Details = event.Id
+	l.Add(entry)
+}
+
+func (l *AuditLog) HandleUnbanRequestResolve(event EventChannelUnbanRequestResolve, msg NotificationMessage) {
+	action := AuditAction(event.Status)
+	switch event.Status {
+	case UnbanRequestStatusApproved:
+		action = AuditActionApproveUnbanRequest
+	case UnbanRequestStatusDenied:
+		action = AuditActionDenyUnbanRequest
+	case UnbanRequestStatusCanceled:
+		action = AuditActionCancelUnbanRequest
+	}
+
+	entry := newAuditEntry(msg, action, event.Broadcaster)
+	entry.Actor = moderatorUser(event.Moderator)
+	entry.Target = event.User
+	entry.Reason = event.ResolutionText
+	entry.Details = event.Id
+	l.Add(entry)
+}
+
+func (l *AuditLog) HandleAutomodMessageUpdate(event EventAutomodMessageUpdate, msg NotificationMessage) {
+	action := AuditAction("automod_" + event.Status)
+	switch event.Status {
+	case AutomodStatusApproved:
+		action = AuditActionAutomodApprove
+	case AutomodStatusDenied:
+		action = AuditActionAutomodDeny
+	case AutomodStatusExpired:
+		action = AuditActionAutomodExpire
+	}
+
+	entry := newAuditEntry(msg, action, event.Broadcaster)
+	entry.Actor = moderatorUser(event.Moderator)
+	entry.Target = event.User
+	entry.MessageID = event.MessageId
+	entry.Message = event.Message.Text
+	entry.Details = event.Category
+	l.Add(entry)
+}
+
+func (l *AuditLog) HandleSuspiciousUserUpdate(event EventChannelSuspiciousUserUpdate, msg NotificationMessage) {
+	entry := newAuditEntry(msg, AuditActionSuspiciousUserUpdate, event.Broadcaster)
+	entry.Actor = moderatorUser(event.Moderator)
+	entry.Target = event.User
+	entry.Details = string(event.LowTrustStatus)
+	l.Add(entry)
+}
+
+func (l *AuditLog) HandleChatClear(event EventChannelChatClear, msg NotificationMessage) {
+	l.Add(newAuditEntry(msg, AuditActionClear, Broadcaster(event)))
+}
+
+func (l *AuditLog) HandleChatClearUserMessages(event EventChannelChatClearUserMessages, msg NotificationMessage) {
+	entry := newAuditEntry(msg, AuditActionClearUserMessages, event.Broadcaster)
+	entry.Target = targetUser(event.Target)
+	l.Add(entry)
+}
+
+func (l *AuditLog) HandleChatMessageDelete(event EventChannelChatMessageDelete, msg NotificationMessage) {
+	entry := newAuditEntry(msg, AuditActionDelete, event.Broadcaster)
+	entry.Target = targetUser(event.Target)
+	entry.MessageID = event.MessageId
+	l.Add(entry)
+}
@@ -0,0 +1,81 @@
+package twitch_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/joeyak/go-twitch-eventsub/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func newAuditMessage(id string, event twitch.EventSubscription, at time.Time) twitch.NotificationMessage {
+	msg := twitch.NotificationMessage{Metadata: twitch.MessageMetadata{MessageID: id, MessageType: "notification", MessageTimestamp: at}}
+	msg.Payload.Subscription.Type = event
+	return msg
+}
+
+func TestAuditLog(t *testing.T) {
+	var buf bytes.Buffer
+	log := twitch.NewAuditLog(twitch.NewJSONAuditSink(&buf))
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	broadcaster := twitch.Broadcaster{BroadcasterUserId: "1337"}
+	moderator := twitch.Moderator{ModeratorUserId: "mod"}
+	troll := twitch.User{UserID: "troll", UserLogin: "troll"}
+
+	var moderate twitch.EventChannelModerate
+	err := json.Unmarshal([]byte(`{
+		"broadcaster_user_id": "1337",
+		"moderator_user_id": "mod",
+		"action": "timeout",
+		"timeout": {"user_id": "troll", "user_login": "troll", "reason": "spam", "expires_at": "`+now.Add(10*time.Minute).Format(time.RFC3339Nano)+`"}
+	}`), &moderate)
+	assert.NoError(t, err)
+	log.HandleModerate(moderate, newAuditMessage("1", twitch.SubChannelModerate, now))
+
+	endsAt := now.Add(10 * time.Minute)
+	log.HandleBan(twitch.EventChannelBan{
+		User: troll, Broadcaster: broadcaster, Moderator: moderator,
+		Reason: "spam", BannedAt: now, EndsAt: &endsAt,
+	}, newAuditMessage("2", twitch.SubChannelBan, now.Add(time.Second)))
+
+	log.HandleWarningSend(twitch.EventChannelWarningSend{
+		Broadcaster: broadcaster, Moderator: moderator, User: troll,
+		Reason: "language", ChatRulesCited: []string{"be nice"},
+	}, newAuditMessage("3", twitch.SubChannelWarningSend, now.Add(time.Minute)))
+
+	log.HandleWarningAcknowledge(twitch.EventChannelWarningAcknowledge{Broadcaster: broadcaster, User: troll}, newAuditMessage("4", twitch.SubChannelWarningAcknowledge, now.Add(2*time.Minute)))
+	log.HandleWarningAcknowledge(twitch.EventChannelWarningAcknowledge{Broadcaster: broadcaster, User: troll}, newAuditMessage("4", twitch.SubChannelWarningAcknowledge, now.Add(2*time.Minute)))
+
+	log.HandleUnbanRequestResolve(twitch.EventChannelUnbanRequestResolve{
+		Broadcaster: broadcaster, Moderator: moderator, User: twitch.User{UserID: "other"},
+		Status: twitch.UnbanRequestStatusDenied, ResolutionText: "no",
+	}, newAuditMessage("5", twitch.SubChannelUnbanRequestResolve, now.Add(3*time.Minute)))
+
+	history := log.History("troll")
+	assert.Len(t, history, 3)
+	assert.Equal(t, twitch.AuditActionTimeout, history[0].Action)
+	assert.Equal(t, "spam", history[0].Reason)
+	assert.Equal(t, 600, history[0].DurationSeconds)
+	assert.Equal(t, "mod", history[0].Actor.UserID)
+	assert.Equal(t, "1337", history[0].SourceChannel.BroadcasterUserId)
+	assert.Equal(t, twitch.AuditActionWarn, history[1].Action)
+	assert.Equal(t, "be nice", history[1].Details)
+	assert.Equal(t, twitch.AuditActionWarningAcknowledge, history[2].Action)
+
+	assert.Len(t, log.Actions("mod"), 3)
+	denied := log.Entries(twitch.AuditFilter{Actions: []twitch.AuditAction{twitch.AuditActionDenyUnbanRequest}})
+	assert.Len(t, denied, 1)
+	assert.Equal(t, "no", denied[0].Reason)
+	assert.Len(t, log.Entries(twitch.AuditFilter{ChannelID: "1337", Limit: 2}), 2)
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	assert.Len(t, lines, 4)
+
+	var entry twitch.AuditEntry
+	assert.NoError(t, json.Unmarshal(lines[0], &entry))
+	assert.Equal(t, history[0], entry)
+	assert.Contains(t, string(lines[0]), `"duration_seconds":600`)
+}
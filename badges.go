@@ -0,0 +1,150 @@
+package twitch
+
+import (
+	"strconv"
+)
+
+const (
+	BadgeBroadcaster   = "broadcaster"
+	BadgeLeadModerator = "lead_moderator"
+	BadgeModerator     = "moderator"
+	BadgeVIP           = "vip"
+	BadgeSubscriber    = "subscriber"
+	BadgeFounder       = "founder"
+	BadgeBits          = "bits"
+	BadgeStaff         = "staff"
+	BadgePartner       = "partner"
+)
+
+type PermissionLevel int
+
+const (
+	PermissionEveryone PermissionLevel = iota
+	PermissionSubscriber
+	PermissionVIP
+	PermissionModerator
+	PermissionBroadcaster
+)
+
+func (l PermissionLevel) String() string {
+	switch l {
+	case PermissionEveryone:
+		return "everyone"
+	case PermissionSubscriber:
+		return "subscriber"
+	case PermissionVIP:
+		return "vip"
+	case PermissionModerator:
+		return "moderator"
+	case PermissionBroadcaster:
+		return "broadcaster"
+	}
+	return "PermissionLevel(" + strconv.Itoa(int(l)) + ")"
+}
+
+type Badges []ChatMessageUserBadge
+
+func (b Badges) Get(setId string) (ChatMessageUserBadge, bool) {
+	for _, badge := range b {
+		if badge.SetId == setId {
+			return badge, true
+		}
+	}
+	return ChatMessageUserBadge{}, false
+}
+
+func (b Badges) Has(setId string) bool {
+	_, ok := b.Get(setId)
+	return ok
+}
+
+func (b Badges) IsBroadcaster() bool {
+	return b.Has(BadgeBroadcaster)
+}
+
+func (b Badges) IsModerator() bool {
+	return b.Has(BadgeModerator) || b.Has(BadgeLeadModerator)
+}
+
+func (b Badges) IsVIP() bool {
+	return b.Has(BadgeVIP)
+}
+
+// IsSubscriber includes founders, whose badge replaces the subscriber badge.
+func (b Badges) IsSubscriber() bool {
+	return b.Has(BadgeSubscriber) || b.Has(BadgeFounder)
+}
+
+func (b Badges) IsFounder() bool {
+	return b.Has(BadgeFounder)
+}
+
+func (b Badges) IsStaff() bool {
+	return b.Has(BadgeStaff)
+}
+
+// SubscriberMonths returns the cumulative months from the subscriber or
+// founder badge info, or 0 if the chatter isn't subscribed or the info is
+// missing.
+func (b Badges) SubscriberMonths() int {
+	badge, ok := b.subscriberBadge()
+	if !ok {
+		return 0
+	}
+
+	months, _ := strconv.Atoi(badge.Info)
+	return months
+}
+
+// SubscriberBadgeMilestone returns the month milestone shown by the
+// subscriber badge, like 12 for the 1 year badge, or 0 without one.
+func (b Badges) SubscriberBadgeMilestone() int {
+	badge, ok := b.subscriberBadge()
+	if !ok {
+		return 0
+	}
+
+	// Badge IDs are the tier times 1000 plus the badge's month milestone
+	id, err := strconv.Atoi(badge.Id)
+	if err != nil {
+		return 0
+	}
+	return id % 1000
+}
+
+func (b Badges) subscriberBadge() (ChatMessageUserBadge, bool) {
+	badge, ok := b.Get(BadgeSubscriber)
+	if !ok {
+		badge, ok = b.Get(BadgeFounder)
+	}
+	return badge, ok
+}
+
+// BitsTier returns the amount of the bits badge, like 1000, or 0 without one.
+func (b Badges) BitsTier() int {
+	badge, ok := b.Get(BadgeBits)
+	if !ok {
+		return 0
+	}
+
+	tier, _ := strconv.Atoi(badge.Id)
+	return tier
+}
+
+func (b Badges) PermissionLevel() PermissionLevel {
+	switch {
+	case b.IsBroadcaster():
+		return PermissionBroadcaster
+	case b.IsModerator():
+		return PermissionModerator
+	case b.IsVIP():
+		return PermissionVIP
+	case b.IsSubscriber():
+		return PermissionSubscriber
+	}
+	return PermissionEveryone
+}
+
+func (b Badges) AtLeast(level PermissionLevel) bool {
+	return b.PermissionLevel() >= level
+}
@@ -0,0 +1,60 @@
+package twitch_test
+
+import (
+	"testing"
+
+	"github.com/joeyak/go-twitch-eventsub/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBadgesPermissionLevel(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Badges   twitch.Badges
+		Expected twitch.PermissionLevel
+	}{
+		{"Everyone", nil, twitch.PermissionEveryone},
+		{"Bits", twitch.Badges{{SetId: "bits", Id: "1000"}}, twitch.PermissionEveryone},
+		{"Subscriber", twitch.Badges{{SetId: "subscriber", Id: "3012", Info: "14"}}, twitch.PermissionSubscriber},
+		{"Founder", twitch.Badges{{SetId: "founder", Id: "0", Info: "3"}}, twitch.PermissionSubscriber},
+		{"VIP", twitch.Badges{{SetId: "vip", Id: "1"}, {SetId: "subscriber", Id: "0"}}, twitch.PermissionVIP},
+		{"Moderator", twitch.Badges{{SetId: "subscriber", Id: "0"}, {SetId: "moderator", Id: "1"}}, twitch.PermissionModerator},
+		{"LeadModerator", twitch.Badges{{SetId: "lead_moderator", Id: "1"}}, twitch.PermissionModerator},
+		{"Broadcaster", twitch.Badges{{SetId: "broadcaster", Id: "1"}}, twitch.PermissionBroadcaster},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			assert.Equal(t, tc.Expected, tc.Badges.PermissionLevel())
+			assert.True(t, tc.Badges.AtLeast(tc.Expected))
+		})
+	}
+}
+
+func TestBadgesSubscriberMonths(t *testing.T) {
+	assert.Equal(t, 14, twitch.Badges{{SetId: "subscriber", Id: "3012", Info: "14"}}.SubscriberMonths())
+	assert.Equal(t, 0, twitch.Badges{{SetId: "subscriber", Id: "3012"}}.SubscriberMonths())
+	assert.Equal(t, 12, twitch.Badges{{SetId: "subscriber", Id: "3012", Info: "14"}}.SubscriberBadgeMilestone())
+	assert.Equal(t, 3, twitch.Badges{{SetId: "founder", Id: "0", Info: "3"}}.SubscriberMonths())
+	assert.Equal(t, 0, twitch.Badges{{SetId: "vip", Id: "1"}}.SubscriberMonths())
+	assert.Equal(t, 5000, twitch.Badges{{SetId: "bits", Id: "5000"}}.BitsTier())
+}
+
+func TestSharedChatBadges(t *testing.T) {
+	event := twitch.EventChannelChatMessage{
+		Broadcaster:       twitch.Broadcaster{BroadcasterUserId: "1"},
+		SourceBroadcaster: twitch.SourceBroadcaster{SourceBroadcasterUserId: "2"},
+		Chatter:           twitch.Chatter{ChatterUserId: "3"},
+		Badges:            twitch.Badges{{SetId: "subscriber", Id: "0"}},
+		SourceBadges:      twitch.Badges{{SetId: "moderator", Id: "1"}},
+	}
+
+	assert.Equal(t, twitch.PermissionSubscriber, event.PermissionLevel())
+	assert.True(t, event.OriginBadges().IsModerator())
+
+	event.SourceBadges = nil
+	assert.False(t, event.OriginBadges().IsModerator())
+
+	event.ChatterUserId = "1"
+	assert.Equal(t, twitch.PermissionBroadcaster, event.PermissionLevel())
+}
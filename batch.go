@@ -0,0 +1,70 @@
+package twitch
+
+import (
+	"context"
+	"sync"
+)
+
+const defaultSubscribeConcurrency = 4
+
+type SubscribeManyOptions struct {
+	// Concurrency is the number of requests sent at once, defaulting to 4.
+	Concurrency int
+	// MaxRetries is how many times a rate limited request is retried,
+	// defaulting to the MaxRetries of the client's RateLimiter. Negative
+	// values disable retries.
+	MaxRetries int
+}
+
+type SubscribeResult struct {
+	Request      SubscribeRequest
+	Subscription PayloadSubscription
+	Response     SubscribeResponse
+	Err          error
+}
+
+func (r SubscribeResult) Ok() bool {
+	return r.Err == nil
+}
+
+// SubscribeMany subscribes to every request through the client's budget. A
+// failed request doesn't stop the others, the results are in the same order
+// as the requests. Rate limited requests wait for the Ratelimit-Reset time
+// in the client's RateLimiter before they are retried.
+func (c *Client) SubscribeMany(ctx context.Context, requests []SubscribeRequest, opts SubscribeManyOptions) []SubscribeResult {
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultSubscribeConcurrency
+	}
+
+	maxRetries := c.rateLimiter().MaxRetries
+	if opts.MaxRetries < 0 {
+		maxRetries = 0
+	} else if opts.MaxRetries > 0 {
+		maxRetries = opts.MaxRetries
+	}
+
+	results := make([]SubscribeResult, len(requests))
+	semaphore := make(chan struct{}, concurrency)
+
+	var wg sync.WaitGroup
+	for i, request := range requests {
+		wg.Add(1)
+		go func(i int, request SubscribeRequest) {
+			defer wg.Done()
+
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			result := SubscribeResult{Request: request}
+			result.Response, result.Err = c.subscribe(ctx, request, maxRetries)
+			if result.Err == nil && len(result.Response.Data) > 0 {
+				result.Subscription = result.Response.Data[0]
+			}
+			results[i] = result
+		}(i, request)
+	}
+	wg.Wait()
+
+	return results
+}
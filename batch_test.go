@@ -0,0 +1,162 @@
+package twitch_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joeyak/go-twitch-eventsub/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubscribeMany(t *testing.T) {
+	t.Parallel()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var rateLimited atomic.Bool
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		request, _ := io.ReadAll(r.Body)
+		r.Body.Close()
+
+		var subscription twitch.SubscriptionRequest
+		json.Unmarshal(request, &subscription)
+
+		switch subscription.Type {
+		case twitch.SubChannelBan:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"Bad Request","status":400,"message":"invalid condition"}`))
+			return
+		case twitch.SubChannelRaid:
+			if rateLimited.CompareAndSwap(false, true) {
+				w.Header().Set("Ratelimit-Reset", "0")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+		}
+
+		response, _ := json.Marshal(twitch.SubscribeResponse{
+			Data: []twitch.PayloadSubscription{{
+				SubscriptionRequest: subscription,
+				ID:                  uuid.NewString(),
+				Status:              string(twitch.SubscriptionStatusEnabled),
+			}},
+		})
+		w.WriteHeader(http.StatusAccepted)
+		w.Write(response)
+	})
+	go http.Serve(listener, mux)
+
+	client := twitch.NewClient()
+	client.SubscriptionUrl = fmt.Sprintf("http://%s", listener.Addr().String())
+
+	events := []twitch.EventSubscription{twitch.SubChannelFollow, twitch.SubChannelBan, twitch.SubChannelRaid, twitch.SubStreamOnline}
+	var requests []twitch.SubscribeRequest
+	for _, event := range events {
+		requests = append(requests, twitch.SubscribeRequest{SessionID: "session", Event: event})
+	}
+
+	results := client.SubscribeMany(context.Background(), requests, twitch.SubscribeManyOptions{Concurrency: 2})
+	assert.Len(t, results, len(events))
+
+	for i, result := range results {
+		assert.Equal(t, events[i], result.Request.Event)
+		if events[i] == twitch.SubChannelBan {
+			var responseErr *twitch.ResponseError
+			assert.ErrorAs(t, result.Err, &responseErr)
+			assert.Equal(t, http.StatusBadRequest, responseErr.StatusCode)
+			continue
+		}
+
+		assert.True(t, result.Ok(), "%s failed: %v", events[i], result.Err)
+		assert.Equal(t, events[i], result.Subscription.Type)
+	}
+
+	assert.True(t, rateLimited.Load(), "rate limit was not hit")
+	assert.Len(t, client.Budget().Subscriptions("session"), 3)
+}
+
+func TestSubscribeManyMaxRetries(t *testing.T) {
+	t.Parallel()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var calls atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Ratelimit-Reset", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	go http.Serve(listener, mux)
+
+	client := twitch.NewClient()
+	client.SubscriptionUrl = fmt.Sprintf("http://%s", listener.Addr().String())
+
+	requests := []twitch.SubscribeRequest{{SessionID: "session", Event: twitch.SubChannelFollow, AccessToken: "max-retries"}}
+	results := client.SubscribeMany(context.Background(), requests, twitch.SubscribeManyOptions{MaxRetries: -1})
+	if assert.Len(t, results, 1) {
+		var responseErr *twitch.ResponseError
+		assert.ErrorAs(t, results[0].Err, &responseErr)
+		assert.Equal(t, http.StatusTooManyRequests, responseErr.StatusCode)
+	}
+	assert.Equal(t, int32(1), calls.Load())
+
+	calls.Store(0)
+	results = client.SubscribeMany(context.Background(), requests, twitch.SubscribeManyOptions{MaxRetries: 1})
+	assert.Error(t, results[0].Err)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestSubscribeEventIsNotRateLimited(t *testing.T) {
+	t.Parallel()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var calls atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Ratelimit-Reset", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	go http.Serve(listener, mux)
+
+	url := fmt.Sprintf("http://%s", listener.Addr().String())
+	request := twitch.SubscribeRequest{SessionID: "session", Event: twitch.SubChannelFollow, AccessToken: "not-limited"}
+
+	_, err = twitch.SubscribeEventUrlWithContext(context.Background(), request, url)
+	var responseErr *twitch.ResponseError
+	assert.ErrorAs(t, err, &responseErr)
+	assert.Equal(t, http.StatusTooManyRequests, responseErr.StatusCode)
+	assert.Equal(t, int32(1), calls.Load())
+
+	limiter := twitch.NewRateLimiter()
+	limiter.MaxRetries = 2
+	limiter.Backoff = 10 * time.Millisecond
+	client := twitch.NewClient()
+	client.SubscriptionUrl = url
+	client.RateLimiter = limiter
+
+	calls.Store(0)
+	_, err = client.Subscribe(context.Background(), request)
+	assert.Error(t, err)
+	assert.Equal(t, int32(3), calls.Load())
+}
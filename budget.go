@@ -0,0 +1,463 @@
+package twitch
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+const (
+	MaxSubscriptionsPerSession = 300
+	MaxSessionsPerToken        = 3
+)
+
+var (
+	ErrSessionSubscriptionLimit = fmt.Errorf("session subscription limit reached")
+	ErrTokenSessionLimit        = fmt.Errorf("token session limit reached")
+	ErrTokenCostLimit           = fmt.Errorf("token cost limit reached")
+	ErrNoSessionID              = fmt.Errorf("subscribe request has no session id")
+	ErrSessionClosed            = fmt.Errorf("session was closed")
+
+	// Subscriptions that don't require the user's authorization cost 1
+	// unless the token belongs to the user in the condition.
+	costlySubscriptions = map[EventSubscription]bool{
+		SubChannelUpdate:                  true,
+		SubChannelRaid:                    true,
+		SubStreamOnline:                   true,
+		SubStreamOffline:                  true,
+		SubUserUpdate:                     true,
+		SubDropEntitlementGrant:           true,
+		SubExtensionBitsTransactionCreate: true,
+	}
+)
+
+// EstimateCost is a pessimistic guess of the cost twitch will charge for a
+// subscription before it is created.
+func EstimateCost(request SubscribeRequest) int {
+	if costlySubscriptions[request.Event] {
+		return 1
+	}
+	return 0
+}
+
+type TrackedSubscription struct {
+	Request      SubscribeRequest
+	Subscription PayloadSubscription
+}
+
+type SessionBudget struct {
+	SessionID     string
+	Subscriptions int
+	Cost          int
+	Remaining     int
+}
+
+type TokenBudget struct {
+	Sessions               int
+	Subscriptions          int
+	RemainingSubscriptions int
+	TotalCost              int
+	MaxTotalCost           int
+}
+
+type tokenKey struct {
+	clientID    string
+	accessToken string
+}
+
+type sessionUsage struct {
+	token         tokenKey
+	subscriptions int
+	cost          int
+	pending       int
+}
+
+type tokenUsage struct {
+	sessions     map[string]bool
+	totalCost    int
+	maxTotalCost int
+	pendingCost  int
+}
+
+// SubscriptionBudget tracks the subscription count of each websocket session
+// and the cost of each token so requests over twitch's limits are refused,
+// or queued when Wait is set, instead of failing on twitch's end.
+//
+// The cost of a token is the sum of the costs of the subscriptions in the
+// budget, only the max total cost is taken from twitch's responses. The
+// SubscribeEvent functions bypass the budget, so subscriptions created with
+// them are not counted.
+type SubscriptionBudget struct {
+	MaxSubscriptionsPerSession int
+	MaxSessionsPerToken        int
+	CostEstimator              func(request SubscribeRequest) int
+	Wait                       bool
+
+	mu            sync.Mutex
+	changed       chan struct{}
+	subscriptions map[string]TrackedSubscription
+	sessions      map[string]*sessionUsage
+	closed        map[string]bool
+	tokens        map[tokenKey]*tokenUsage
+}
+
+func NewSubscriptionBudget() *SubscriptionBudget {
+	return &SubscriptionBudget{
+		MaxSubscriptionsPerSession: MaxSubscriptionsPerSession,
+		MaxSessionsPerToken:        MaxSessionsPerToken,
+		CostEstimator:              EstimateCost,
+		changed:                    make(chan struct{}),
+		subscriptions:              map[string]TrackedSubscription{},
+		sessions:                   map[string]*sessionUsage{},
+		closed:                     map[string]bool{},
+		tokens:                     map[tokenKey]*tokenUsage{},
+	}
+}
+
+func (b *SubscriptionBudget) Subscribe(ctx context.Context, request SubscribeRequest) (SubscribeResponse, error) {
+	return b.SubscribeUrl(ctx, request, twitchEventSubUrl)
+}
+
+func (b *SubscriptionBudget) SubscribeUrl(ctx context.Context, request SubscribeRequest, url string) (SubscribeResponse, error) {
+	return b.subscribe(ctx, request, url, nil, 0)
+}
+
+// subscribe reserves the request in the budget before sending it with
+// subscribeEvent.
+func (b *SubscriptionBudget) subscribe(ctx context.Context, request SubscribeRequest, url string, limiter *RateLimiter, maxRetries int) (SubscribeResponse, error) {
+	cost, err := b.reserve(ctx, request)
+	if err != nil {
+		return SubscribeResponse{}, err
+	}
+
+	response, err := subscribeEvent(ctx, request, url, limiter, maxRetries)
+	closed := b.commit(request, cost, response, err == nil)
+	if err == nil && closed {
+		return SubscribeResponse{}, fmt.Errorf("could not track subscription: %w: %s", ErrSessionClosed, request.SessionID)
+	}
+	return response, err
+}
+
+func (b *SubscriptionBudget) reserve(ctx context.Context, request SubscribeRequest) (int, error) {
+	if request.SessionID == "" {
+		return 0, ErrNoSessionID
+	}
+
+	cost := 0
+	if b.CostEstimator != nil {
+		cost = b.CostEstimator(request)
+	}
+	key := tokenKey{request.ClientID, request.AccessToken}
+
+	for {
+		b.mu.Lock()
+		err := b.check(request.SessionID, key, cost)
+		if err == nil {
+			session := b.session(request.SessionID, key)
+			token := b.token(key)
+			token.sessions[request.SessionID] = true
+			token.pendingCost += cost
+			session.pending++
+			b.mu.Unlock()
+			return cost, nil
+		}
+		changed := b.changed
+		b.mu.Unlock()
+
+		if !b.Wait {
+			return 0, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return 0, fmt.Errorf("could not wait for subscription budget: %w", ctx.Err())
+		case <-changed:
+		}
+	}
+}
+
+func (b *SubscriptionBudget) check(sessionID string, key tokenKey, cost int) error {
+	token := b.tokens[key]
+	session := b.sessions[sessionID]
+
+	if b.closed[sessionID] {
+		return fmt.Errorf("%w: %s", ErrSessionClosed, sessionID)
+	}
+
+	if session != nil && session.subscriptions+session.pending >= b.MaxSubscriptionsPerSession {
+		return fmt.Errorf("%w: session %s has %d subscriptions", ErrSessionSubscriptionLimit, sessionID, session.subscriptions+session.pending)
+	}
+
+	if token == nil {
+		return nil
+	}
+
+	if !token.sessions[sessionID] && len(token.sessions) >= b.MaxSessionsPerToken {
+		return fmt.Errorf("%w: token already has %d sessions", ErrTokenSessionLimit, len(token.sessions))
+	}
+
+	if cost > 0 && token.maxTotalCost > 0 && token.totalCost+token.pendingCost+cost > token.maxTotalCost {
+		return fmt.Errorf("%w: total cost %d of %d", ErrTokenCostLimit, token.totalCost+token.pendingCost, token.maxTotalCost)
+	}
+
+	return nil
+}
+
+// commit releases the reservation of the request and tracks the created
+// subscriptions. It reports whether the session was closed meanwhile, in
+// which case the subscriptions are not tracked.
+func (b *SubscriptionBudget) commit(request SubscribeRequest, cost int, response SubscribeResponse, ok bool) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	defer b.notify()
+
+	key := tokenKey{request.ClientID, request.AccessToken}
+	session := b.session(request.SessionID, key)
+	token := b.token(key)
+	session.pending--
+	token.pendingCost -= cost
+
+	closed := b.closed[request.SessionID]
+	if !ok || closed {
+		if session.subscriptions == 0 && session.pending == 0 {
+			delete(b.sessions, request.SessionID)
+			delete(token.sessions, request.SessionID)
+		}
+		return closed
+	}
+
+	token.maxTotalCost = response.MaxTotalCost
+
+	for _, subscription := range response.Data {
+		b.subscriptions[subscription.ID] = TrackedSubscription{
+			Request:      request,
+			Subscription: subscription,
+		}
+		session.subscriptions++
+		session.cost += subscription.Cost
+		token.totalCost += subscription.Cost
+	}
+	return false
+}
+
+func (b *SubscriptionBudget) session(sessionID string, key tokenKey) *sessionUsage {
+	session, ok := b.sessions[sessionID]
+	if !ok {
+		session = &sessionUsage{token: key}
+		b.sessions[sessionID] = session
+	}
+	return session
+}
+
+func (b *SubscriptionBudget) token(key tokenKey) *tokenUsage {
+	token, ok := b.tokens[key]
+	if !ok {
+		token = &tokenUsage{sessions: map[string]bool{}}
+		b.tokens[key] = token
+	}
+	return token
+}
+
+func (b *SubscriptionBudget) notify() {
+	close(b.changed)
+	b.changed = make(chan struct{})
+}
+
+// Remove stops tracking a subscription that was deleted or revoked.
+func (b *SubscriptionBudget) Remove(subscriptionID string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	tracked, ok := b.subscriptions[subscriptionID]
+	if !ok {
+		return
+	}
+	delete(b.subscriptions, subscriptionID)
+
+	if session, ok := b.sessions[tracked.Request.SessionID]; ok {
+		session.subscriptions--
+		session.cost -= tracked.Subscription.Cost
+	}
+	if token, ok := b.tokens[tokenKey{tracked.Request.ClientID, tracked.Request.AccessToken}]; ok {
+		token.totalCost -= tracked.Subscription.Cost
+	}
+
+	b.notify()
+}
+
+// RemoveSession releases every subscription of a session, which twitch
+// deletes once the websocket is closed. Requests for the session are
+// refused with ErrSessionClosed from then on.
+func (b *SubscriptionBudget) RemoveSession(sessionID string) {
+	b.removeSession(sessionID)
+}
+
+// removeSession is RemoveSession returning the released subscriptions.
+func (b *SubscriptionBudget) removeSession(sessionID string) []TrackedSubscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if sessionID == "" || b.closed[sessionID] {
+		return nil
+	}
+	b.closed[sessionID] = true
+
+	var subscriptions []TrackedSubscription
+	for id, tracked := range b.subscriptions {
+		if tracked.Request.SessionID == sessionID {
+			subscriptions = append(subscriptions, tracked)
+			delete(b.subscriptions, id)
+		}
+	}
+
+	session, ok := b.sessions[sessionID]
+	if !ok {
+		return subscriptions
+	}
+
+	if token, ok := b.tokens[session.token]; ok {
+		delete(token.sessions, sessionID)
+		token.totalCost -= session.cost
+	}
+
+	// reservations still pending are released by commit
+	session.subscriptions = 0
+	session.cost = 0
+	if session.pending == 0 {
+		delete(b.sessions, sessionID)
+	}
+
+	b.notify()
+	return subscriptions
+}
+
+// moveSession keeps the subscriptions of a session that reconnected under
+// another session ID.
+func (b *SubscriptionBudget) moveSession(from, to string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	session, ok := b.sessions[from]
+	if !ok {
+		return
+	}
+	delete(b.sessions, from)
+	b.sessions[to] = session
+
+	for id, tracked := range b.subscriptions {
+		if tracked.Request.SessionID == from {
+			tracked.Request.SessionID = to
+			tracked.Subscription.Transport.SessionID = to
+			b.subscriptions[id] = tracked
+		}
+	}
+
+	if token, ok := b.tokens[session.token]; ok {
+		delete(token.sessions, from)
+		token.sessions[to] = true
+	}
+
+	b.notify()
+}
+
+func (b *SubscriptionBudget) Subscriptions(sessionID string) []TrackedSubscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var subscriptions []TrackedSubscription
+	for _, tracked := range b.subscriptions {
+		if tracked.Request.SessionID == sessionID {
+			subscriptions = append(subscriptions, tracked)
+		}
+	}
+	return subscriptions
+}
+
+func (b *SubscriptionBudget) Session(sessionID string) SessionBudget {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	budget := SessionBudget{
+		SessionID: sessionID,
+		Remaining: b.MaxSubscriptionsPerSession,
+	}
+	if session, ok := b.sessions[sessionID]; ok {
+		budget.Subscriptions = session.subscriptions
+		budget.Cost = session.cost
+		budget.Remaining -= session.subscriptions + session.pending
+	}
+	return budget
+}
+
+func (b *SubscriptionBudget) Token(clientID, accessToken string) TokenBudget {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.tokenBudget(tokenKey{clientID, accessToken})
+}
+
+func (b *SubscriptionBudget) tokenBudget(key tokenKey) TokenBudget {
+	budget := TokenBudget{
+		RemainingSubscriptions: b.MaxSessionsPerToken * b.MaxSubscriptionsPerSession,
+	}
+
+	token, ok := b.tokens[key]
+	if !ok {
+		return budget
+	}
+
+	budget.Sessions = len(token.sessions)
+	budget.TotalCost = token.totalCost
+	budget.MaxTotalCost = token.maxTotalCost
+	budget.RemainingSubscriptions = (b.MaxSessionsPerToken - len(token.sessions)) * b.MaxSubscriptionsPerSession
+	for sessionID := range token.sessions {
+		session := b.sessions[sessionID]
+		if session == nil {
+			budget.RemainingSubscriptions += b.MaxSubscriptionsPerSession
+			continue
+		}
+		budget.Subscriptions += session.subscriptions
+		budget.RemainingSubscriptions += b.MaxSubscriptionsPerSession - session.subscriptions - session.pending
+	}
+	return budget
+}
+
+// EstimateChannels returns how many more channels can be onboarded with the
+// token when each channel needs a subscription to every event in events.
+func (b *SubscriptionBudget) EstimateChannels(clientID, accessToken string, events []EventSubscription) int {
+	if len(events) == 0 {
+		return 0
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	key := tokenKey{clientID, accessToken}
+	budget := b.tokenBudget(key)
+
+	channels := budget.RemainingSubscriptions / len(events)
+
+	cost := 0
+	if b.CostEstimator != nil {
+		for _, event := range events {
+			cost += b.CostEstimator(SubscribeRequest{ClientID: clientID, AccessToken: accessToken, Event: event})
+		}
+	}
+
+	if cost > 0 && budget.MaxTotalCost > 0 {
+		pending := 0
+		if token, ok := b.tokens[key]; ok {
+			pending = token.pendingCost
+		}
+
+		remainingCost := budget.MaxTotalCost - budget.TotalCost - pending
+		if remainingCost < 0 {
+			remainingCost = 0
+		}
+		if remainingCost/cost < channels {
+			channels = remainingCost / cost
+		}
+	}
+
+	return channels
+}
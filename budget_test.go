@@ -0,0 +1,207 @@
+package twitch_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joeyak/go-twitch-eventsub/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func newSubscriptionServer(t *testing.T, cost, maxTotalCost int) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	totalCost := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		request, _ := io.ReadAll(r.Body)
+		r.Body.Close()
+
+		var subscription twitch.SubscriptionRequest
+		if err := json.Unmarshal(request, &subscription); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		totalCost += cost
+		response, _ := json.Marshal(twitch.SubscribeResponse{
+			Data: []twitch.PayloadSubscription{{
+				SubscriptionRequest: subscription,
+				ID:                  uuid.NewString(),
+				Status:              "enabled",
+				Cost:                cost,
+				CreateAt:            time.Now(),
+			}},
+			Total:        1,
+			TotalCost:    totalCost,
+			MaxTotalCost: maxTotalCost,
+		})
+		w.WriteHeader(http.StatusAccepted)
+		w.Write(response)
+	})
+
+	go http.Serve(listener, mux)
+	return fmt.Sprintf("http://%s", listener.Addr().String())
+}
+
+func TestBudgetSessionLimit(t *testing.T) {
+	t.Parallel()
+
+	url := newSubscriptionServer(t, 0, 10)
+	budget := twitch.NewSubscriptionBudget()
+	budget.MaxSubscriptionsPerSession = 2
+
+	request := twitch.SubscribeRequest{SessionID: "session", Event: twitch.SubChannelFollow}
+	for i := 0; i < 2; i++ {
+		_, err := budget.SubscribeUrl(context.Background(), request, url)
+		assert.NoError(t, err)
+	}
+
+	_, err := budget.SubscribeUrl(context.Background(), request, url)
+	assert.ErrorIs(t, err, twitch.ErrSessionSubscriptionLimit)
+	assert.Equal(t, 0, budget.Session("session").Remaining)
+
+	subscriptions := budget.Subscriptions("session")
+	assert.Len(t, subscriptions, 2)
+
+	budget.Remove(subscriptions[0].Subscription.ID)
+	assert.Equal(t, 1, budget.Session("session").Remaining)
+}
+
+func TestBudgetTokenSessionLimit(t *testing.T) {
+	t.Parallel()
+
+	url := newSubscriptionServer(t, 0, 10)
+	budget := twitch.NewSubscriptionBudget()
+
+	for i := 0; i < twitch.MaxSessionsPerToken; i++ {
+		_, err := budget.SubscribeUrl(context.Background(), twitch.SubscribeRequest{
+			SessionID: fmt.Sprint(i),
+			Event:     twitch.SubChannelFollow,
+		}, url)
+		assert.NoError(t, err)
+	}
+
+	_, err := budget.SubscribeUrl(context.Background(), twitch.SubscribeRequest{
+		SessionID: "extra",
+		Event:     twitch.SubChannelFollow,
+	}, url)
+	assert.ErrorIs(t, err, twitch.ErrTokenSessionLimit)
+
+	budget.RemoveSession("0")
+	_, err = budget.SubscribeUrl(context.Background(), twitch.SubscribeRequest{
+		SessionID: "extra",
+		Event:     twitch.SubChannelFollow,
+	}, url)
+	assert.NoError(t, err)
+}
+
+func TestBudgetCostLimit(t *testing.T) {
+	t.Parallel()
+
+	url := newSubscriptionServer(t, 1, 2)
+	budget := twitch.NewSubscriptionBudget()
+
+	request := twitch.SubscribeRequest{SessionID: "session", Event: twitch.SubStreamOnline}
+	var responses []twitch.SubscribeResponse
+	for i := 0; i < 2; i++ {
+		response, err := budget.SubscribeUrl(context.Background(), request, url)
+		assert.NoError(t, err)
+		responses = append(responses, response)
+	}
+
+	assert.Equal(t, 0, budget.EstimateChannels("", "", []twitch.EventSubscription{twitch.SubStreamOnline}))
+	assert.Equal(t, 449, budget.EstimateChannels("", "", []twitch.EventSubscription{twitch.SubChannelFollow, twitch.SubChannelSubscribe}))
+
+	_, err := budget.SubscribeUrl(context.Background(), request, url)
+	assert.ErrorIs(t, err, twitch.ErrTokenCostLimit)
+
+	token := budget.Token("", "")
+	assert.Equal(t, 2, token.TotalCost)
+	assert.Equal(t, 2, token.MaxTotalCost)
+	assert.Equal(t, 2, token.Subscriptions)
+
+	// the cost of removed subscriptions isn't counted again from the total
+	// cost of the next response
+	budget.Remove(responses[0].Data[0].ID)
+	assert.Equal(t, 1, budget.Token("", "").TotalCost)
+
+	_, err = budget.SubscribeUrl(context.Background(), request, url)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, budget.Token("", "").TotalCost)
+}
+
+func TestBudgetWait(t *testing.T) {
+	t.Parallel()
+
+	url := newSubscriptionServer(t, 0, 10)
+	budget := twitch.NewSubscriptionBudget()
+	budget.MaxSubscriptionsPerSession = 1
+	budget.Wait = true
+
+	request := twitch.SubscribeRequest{SessionID: "session", Event: twitch.SubChannelFollow}
+	_, err := budget.SubscribeUrl(context.Background(), request, url)
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = budget.SubscribeUrl(ctx, request, url)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		budget.Remove(budget.Subscriptions("session")[0].Subscription.ID)
+	}()
+
+	_, err = budget.SubscribeUrl(context.Background(), request, url)
+	assert.NoError(t, err)
+}
+
+func TestBudgetRemoveSessionPending(t *testing.T) {
+	t.Parallel()
+
+	url := newSubscriptionServer(t, 0, 10)
+	received := make(chan struct{})
+	release := make(chan struct{})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go http.Serve(listener, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(received)
+		<-release
+		http.Redirect(w, r, url, http.StatusTemporaryRedirect)
+	}))
+
+	budget := twitch.NewSubscriptionBudget()
+	request := twitch.SubscribeRequest{SessionID: "session", Event: twitch.SubChannelFollow}
+
+	done := make(chan error)
+	go func() {
+		_, err := budget.SubscribeUrl(context.Background(), request, fmt.Sprintf("http://%s", listener.Addr().String()))
+		done <- err
+	}()
+
+	// the session is removed while the subscription is being created
+	<-received
+	budget.RemoveSession("session")
+	close(release)
+
+	assert.ErrorIs(t, <-done, twitch.ErrSessionClosed)
+	assert.Empty(t, budget.Subscriptions("session"))
+	assert.Equal(t, 0, budget.Token("", "").Subscriptions)
+
+	_, err = budget.SubscribeUrl(context.Background(), request, url)
+	assert.ErrorIs(t, err, twitch.ErrSessionClosed)
+}
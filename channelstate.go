@@ -0,0 +1,260 @@
+package twitch
+
+import (
+	"reflect"
+	"time"
+)
+
+type ChatSettings struct {
+	EmoteMode                   bool `json:"emote_mode"`
+	FollowerMode                bool `json:"follower_mode"`
+	FollowerModeDurationMinutes int  `json:"follower_mode_duration_minutes"`
+	SlowMode                    bool `json:"slow_mode"`
+	SlowModeWaitTimeSeconds     int  `json:"slow_mode_wait_time_seconds"`
+	SubscriberMode              bool `json:"subscriber_mode"`
+	UniqueChatMode              bool `json:"unique_chat_mode"`
+}
+
+type ShieldMode struct {
+	Moderator
+
+	IsActive  bool      `json:"is_active"`
+	StartedAt time.Time `json:"started_at"`
+	StoppedAt time.Time `json:"stopped_at"`
+}
+
+type ChannelInfo struct {
+	Title                       string   `json:"title"`
+	Language                    string   `json:"language"`
+	CategoryID                  string   `json:"category_id"`
+	CategoryName                string   `json:"category_name"`
+	ContentClassificationLabels []string `json:"content_classification_labels"`
+}
+
+type AutomodLevels struct {
+	OverallLevel            *int `json:"overall_level,omitempty"`
+	Disability              int  `json:"disability"`
+	Aggression              int  `json:"aggression"`
+	SexualitySexOrGender    int  `json:"sexuality_sex_or_gender"`
+	Misogyny                int  `json:"misogyny"`
+	Bullying                int  `json:"bullying"`
+	Swearing                int  `json:"swearing"`
+	RaceEthnicityOrReligion int  `json:"race_ethnicity_or_religion"`
+	SexBasedTerms           int  `json:"sex_based_terms"`
+}
+
+type SharedChatSession struct {
+	HostBroadcaster
+
+	SessionId    string        `json:"session_id"`
+	Participants []Broadcaster `json:"participants"`
+}
+
+func (s SharedChatSession) IsActive() bool {
+	return s.SessionId != ""
+}
+
+type ChannelStateField string
+
+const (
+	ChannelStateChatSettings ChannelStateField = "chat_settings"
+	ChannelStateShieldMode   ChannelStateField = "shield_mode"
+	ChannelStateInfo         ChannelStateField = "info"
+	ChannelStateAutomod      ChannelStateField = "automod"
+	ChannelStateSharedChat   ChannelStateField = "shared_chat"
+)
+
+// ChannelState is a snapshot of a channel. Parts of the state stay empty
+// until the first event describing them is received.
+type ChannelState struct {
+	Broadcaster
+
+	ChatSettings ChatSettings      `json:"chat_settings"`
+	ShieldMode   ShieldMode        `json:"shield_mode"`
+	Info         ChannelInfo       `json:"info"`
+	Automod      AutomodLevels     `json:"automod"`
+	SharedChat   SharedChatSession `json:"shared_chat"`
+}
+
+// Diff returns the parts of the state that differ from old.
+func (s ChannelState) Diff(old ChannelState) []ChannelStateField {
+	var fields []ChannelStateField
+	if s.ChatSettings != old.ChatSettings {
+		fields = append(fields, ChannelStateChatSettings)
+	}
+	if s.ShieldMode != old.ShieldMode {
+		fields = append(fields, ChannelStateShieldMode)
+	}
+	if !reflect.DeepEqual(s.Info, old.Info) {
+		fields = append(fields, ChannelStateInfo)
+	}
+	if !reflect.DeepEqual(s.Automod, old.Automod) {
+		fields = append(fields, ChannelStateAutomod)
+	}
+	if !reflect.DeepEqual(s.SharedChat, old.SharedChat) {
+		fields = append(fields, ChannelStateSharedChat)
+	}
+	return fields
+}
+
+// ChannelMirror applies channel events to a ChannelState per broadcaster.
+// Each part of the state keeps the timestamp of the message that set it so
+// a late event doesn't overwrite a newer one.
+//
+// Shared chat sessions are kept by the SharedChatTracker returned by
+// SharedChat, which can tell the origin of chat events without its handlers
+// being wired to a client as well.
+type ChannelMirror struct {
+	notifier
+
+	sharedChat *SharedChatTracker
+	states     map[string]ChannelState
+	updated    map[string]map[ChannelStateField]time.Time
+	onChange   func(old, new ChannelState)
+}
+
+func NewChannelMirror() *ChannelMirror {
+	return &ChannelMirror{
+		sharedChat: NewSharedChatTracker(),
+		states:     map[string]ChannelState{},
+		updated:    map[string]map[ChannelStateField]time.Time{},
+	}
+}
+
+// SharedChat returns the tracker of the shared chat sessions of the mirror.
+func (m *ChannelMirror) SharedChat() *SharedChatTracker {
+	return m.sharedChat
+}
+
+// OnChange is called with the state before and after an event changed it.
+// Use new.Diff(old) to find the parts that changed.
+func (m *ChannelMirror) OnChange(callback func(old, new ChannelState)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onChange = callback
+}
+
+func (m *ChannelMirror) State(broadcasterID string) (ChannelState, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	state, ok := m.states[broadcasterID]
+	return state, ok
+}
+
+func (m *ChannelMirror) HandleChatSettingsUpdate(event EventChannelChatSettingsUpdate, msg NotificationMessage) {
+	m.apply(event.Broadcaster, ChannelStateChatSettings, msg, func(state *ChannelState) {
+		state.ChatSettings = ChatSettings{
+			EmoteMode:                   event.EmoteMode,
+			FollowerMode:                event.FollowerMode,
+			FollowerModeDurationMinutes: event.FollowerModeDurationMinutes,
+			SlowMode:                    event.SlowMode,
+			SlowModeWaitTimeSeconds:     event.SlowModeWaitTimeSeconds,
+			SubscriberMode:              event.SubscriberMode,
+			UniqueChatMode:              event.UniqueChatMode,
+		}
+	})
+}
+
+func (m *ChannelMirror) HandleShieldModeBegin(event EventChannelShieldModeBegin, msg NotificationMessage) {
+	m.apply(event.Broadcaster, ChannelStateShieldMode, msg, func(state *ChannelState) {
+		state.ShieldMode = ShieldMode{
+			Moderator: event.Moderator,
+			IsActive:  true,
+			StartedAt: event.StartedAt,
+		}
+	})
+}
+
+func (m *ChannelMirror) HandleShieldModeEnd(event EventChannelShieldModeEnd, msg NotificationMessage) {
+	m.apply(event.Broadcaster, ChannelStateShieldMode, msg, func(state *ChannelState) {
+		state.ShieldMode = ShieldMode{
+			Moderator: event.Moderator,
+			StartedAt: state.ShieldMode.StartedAt,
+			StoppedAt: event.StoppedAt,
+		}
+	})
+}
+
+func (m *ChannelMirror) HandleChannelUpdate(event EventChannelUpdate, msg NotificationMessage) {
+	m.apply(event.Broadcaster, ChannelStateInfo, msg, func(state *ChannelState) {
+		state.Info = ChannelInfo{
+			Title:                       event.Title,
+			Language:                    event.Language,
+			CategoryID:                  event.CategoryID,
+			CategoryName:                event.CategoryName,
+			ContentClassificationLabels: event.ContentClassificationLabels,
+		}
+	})
+}
+
+func (m *ChannelMirror) HandleAutomodSettingsUpdate(event EventAutomodSettingsUpdate, msg NotificationMessage) {
+	m.apply(event.Broadcaster, ChannelStateAutomod, msg, func(state *ChannelState) {
+		state.Automod = AutomodLevels{
+			OverallLevel:            event.OverallLevel,
+			Disability:              event.Disability,
+			Aggression:              event.Aggression,
+			SexualitySexOrGender:    event.SexualitySexOrGender,
+			Misogyny:                event.Misogyny,
+			Bullying:                event.Bullying,
+			Swearing:                event.Swearing,
+			RaceEthnicityOrReligion: event.RaceEthnicityOrReligion,
+			SexBasedTerms:           event.SexBasedTerms,
+		}
+	})
+}
+
+func (m *ChannelMirror) HandleSharedChatBegin(event EventChannelSharedChatBegin, msg NotificationMessage) {
+	m.sharedChat.HandleBegin(event, msg)
+	m.applySharedChat(event.Broadcaster, msg)
+}
+
+func (m *ChannelMirror) HandleSharedChatUpdate(event EventChannelSharedChatUpdate, msg NotificationMessage) {
+	m.sharedChat.HandleUpdate(event, msg)
+	m.applySharedChat(event.Broadcaster, msg)
+}
+
+func (m *ChannelMirror) HandleSharedChatEnd(event EventChannelSharedChatEnd, msg NotificationMessage) {
+	m.sharedChat.HandleEnd(event, msg)
+	m.applySharedChat(event.Broadcaster, msg)
+}
+
+// applySharedChat copies the session the shared chat tracker kept for the
+// broadcaster into the state.
+func (m *ChannelMirror) applySharedChat(broadcaster Broadcaster, msg NotificationMessage) {
+	m.apply(broadcaster, ChannelStateSharedChat, msg, func(state *ChannelState) {
+		state.SharedChat, _ = m.sharedChat.Session(broadcaster.BroadcasterUserId)
+	})
+}
+
+func (m *ChannelMirror) apply(broadcaster Broadcaster, field ChannelStateField, msg NotificationMessage, update func(state *ChannelState)) {
+	at := msg.Metadata.MessageTimestamp
+
+	m.mu.Lock()
+	updated, ok := m.updated[broadcaster.BroadcasterUserId]
+	if !ok {
+		updated = map[ChannelStateField]time.Time{}
+		m.updated[broadcaster.BroadcasterUserId] = updated
+	}
+	if isOutdated(updated, field, at) {
+		m.mu.Unlock()
+		return
+	}
+
+	old := m.states[broadcaster.BroadcasterUserId]
+	state := old
+	state.Broadcaster = broadcaster
+	update(&state)
+	m.states[broadcaster.BroadcasterUserId] = state
+
+	if len(state.Diff(old)) == 0 {
+		m.mu.Unlock()
+		return
+	}
+
+	onChange := m.onChange
+	m.unlockAndNotify(func() {
+		if onChange != nil {
+			onChange(old, state)
+		}
+	})
+}
@@ -0,0 +1,71 @@
+package twitch_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/joeyak/go-twitch-eventsub/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChannelMirror(t *testing.T) {
+	mirror := twitch.NewChannelMirror()
+
+	var diffs [][]twitch.ChannelStateField
+	var last twitch.ChannelState
+	mirror.OnChange(func(old, new twitch.ChannelState) {
+		diffs = append(diffs, new.Diff(old))
+		last = new
+	})
+
+	now := time.Now()
+	broadcaster := twitch.Broadcaster{BroadcasterUserId: "1337"}
+
+	mirror.HandleChatSettingsUpdate(twitch.EventChannelChatSettingsUpdate{Broadcaster: broadcaster, SlowMode: true, SlowModeWaitTimeSeconds: 30}, newNotificationAt(now))
+	mirror.HandleChatSettingsUpdate(twitch.EventChannelChatSettingsUpdate{Broadcaster: broadcaster, SlowMode: true, SlowModeWaitTimeSeconds: 30}, newNotificationAt(now.Add(time.Second)))
+	mirror.HandleChannelUpdate(twitch.EventChannelUpdate{Broadcaster: broadcaster, Title: "new title", ContentClassificationLabels: []string{"Gambling"}}, newNotificationAt(now))
+	mirror.HandleShieldModeBegin(twitch.EventChannelShieldModeBegin{Broadcaster: broadcaster, StartedAt: now}, newNotificationAt(now))
+	mirror.HandleSharedChatBegin(twitch.EventChannelSharedChatBegin{
+		Broadcaster:  broadcaster,
+		SessionId:    "session",
+		Participants: []twitch.Broadcaster{broadcaster, {BroadcasterUserId: "42"}},
+	}, newNotificationAt(now))
+
+	// a late update doesn't overwrite the newer settings
+	mirror.HandleChatSettingsUpdate(twitch.EventChannelChatSettingsUpdate{Broadcaster: broadcaster}, newNotificationAt(now.Add(-time.Second)))
+
+	assert.Equal(t, [][]twitch.ChannelStateField{
+		{twitch.ChannelStateChatSettings},
+		{twitch.ChannelStateInfo},
+		{twitch.ChannelStateShieldMode},
+		{twitch.ChannelStateSharedChat},
+	}, diffs)
+
+	state, ok := mirror.State("1337")
+	assert.True(t, ok)
+	assert.Equal(t, last, state)
+	assert.True(t, state.ChatSettings.SlowMode)
+	assert.Equal(t, 30, state.ChatSettings.SlowModeWaitTimeSeconds)
+	assert.True(t, state.ShieldMode.IsActive)
+	assert.Equal(t, "new title", state.Info.Title)
+	assert.True(t, state.SharedChat.IsActive())
+	assert.Len(t, state.SharedChat.Participants, 2)
+
+	// the shared chat tracker of the mirror has the same session
+	origin := mirror.SharedChat().MessageOrigin(twitch.EventChannelChatMessage{
+		Broadcaster:       broadcaster,
+		SourceBroadcaster: twitch.SourceBroadcaster{SourceBroadcasterUserId: "42"},
+	})
+	assert.True(t, origin.IsSharedChat)
+	assert.Equal(t, state.SharedChat, origin.Session)
+
+	mirror.HandleShieldModeEnd(twitch.EventChannelShieldModeEnd{Broadcaster: broadcaster, StoppedAt: now.Add(time.Minute)}, newNotificationAt(now.Add(time.Minute)))
+	mirror.HandleSharedChatEnd(twitch.EventChannelSharedChatEnd{Broadcaster: broadcaster, SessionId: "session"}, newNotificationAt(now.Add(time.Minute)))
+
+	state, _ = mirror.State("1337")
+	assert.False(t, state.ShieldMode.IsActive)
+	assert.Equal(t, now, state.ShieldMode.StartedAt)
+	assert.False(t, state.SharedChat.IsActive())
+	_, ok = mirror.SharedChat().Session("1337")
+	assert.False(t, ok)
+}
@@ -0,0 +1,313 @@
+package twitch
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+const DefaultMaxDonations = 100
+
+type CharityDonation struct {
+	User
+
+	ID     string    `json:"id"`
+	Amount Money     `json:"amount"`
+	At     time.Time `json:"at"`
+}
+
+type CharityDonor struct {
+	User
+
+	Total     Money `json:"total"`
+	Donations int   `json:"donations"`
+}
+
+type CharityState struct {
+	Broadcaster
+
+	CampaignID         string            `json:"campaign_id"`
+	CharityName        string            `json:"charity_name"`
+	CharityDescription string            `json:"charity_description"`
+	CharityLogo        string            `json:"charity_logo"`
+	CharityWebsite     string            `json:"charity_website"`
+	CurrentAmount      Money             `json:"current_amount"`
+	TargetAmount       Money             `json:"target_amount"`
+	StartedAt          time.Time         `json:"started_at"`
+	StoppedAt          time.Time         `json:"stopped_at"`
+	UpdatedAt          time.Time         `json:"updated_at"`
+	Donations          []CharityDonation `json:"donations"`
+	Donors             []CharityDonor    `json:"donors"`
+	Samples            []ProgressSample  `json:"samples"`
+}
+
+func (s CharityState) IsStopped() bool {
+	return !s.StoppedAt.IsZero()
+}
+
+func (s CharityState) IsReached() bool {
+	cmp, err := s.CurrentAmount.Cmp(s.TargetAmount)
+	return err == nil && !s.TargetAmount.IsZero() && cmp >= 0
+}
+
+// Percent returns the progress towards the target from 0 to 1.
+func (s CharityState) Percent() float64 {
+	current, target := s.amounts()
+	return percent(current, target)
+}
+
+// ETA estimates when the target will be reached from the recent progress.
+func (s CharityState) ETA() (time.Time, bool) {
+	if s.IsStopped() {
+		return time.Time{}, false
+	}
+
+	current, target := s.amounts()
+	return estimateReached(s.Samples, current, target)
+}
+
+// TopDonors returns up to n donors with the highest total. A negative n
+// returns all of them.
+func (s CharityState) TopDonors(n int) []CharityDonor {
+	donors := append([]CharityDonor(nil), s.Donors...)
+	if n >= 0 && len(donors) > n {
+		donors = donors[:n]
+	}
+	return donors
+}
+
+// amounts returns the current and target amounts in the same minor units.
+func (s CharityState) amounts() (int, int) {
+	current := s.CurrentAmount.Rescale(s.TargetAmount.DecimalPlaces)
+	target := s.TargetAmount.Rescale(current.DecimalPlaces)
+	return current.Value, target.Value
+}
+
+func (s CharityState) addDonation(donation CharityDonation, maxDonations int) CharityState {
+	for _, seen := range s.Donations {
+		if seen.ID == donation.ID {
+			return s
+		}
+	}
+
+	s.Donations = append(append([]CharityDonation(nil), s.Donations...), donation)
+	if maxDonations >= 0 && len(s.Donations) > maxDonations {
+		s.Donations = s.Donations[len(s.Donations)-maxDonations:]
+	}
+
+	s.Donors = append([]CharityDonor(nil), s.Donors...)
+	found := false
+	for i, donor := range s.Donors {
+		if donor.UserID != donation.UserID {
+			continue
+		}
+
+		total, err := donor.Total.Add(donation.Amount)
+		if err == nil {
+			s.Donors[i].Total = total
+			s.Donors[i].Donations++
+		}
+		found = true
+		break
+	}
+	if !found {
+		s.Donors = append(s.Donors, CharityDonor{User: donation.User, Total: donation.Amount, Donations: 1})
+	}
+
+	sort.SliceStable(s.Donors, func(i, j int) bool {
+		cmp, _ := s.Donors[i].Total.Cmp(s.Donors[j].Total)
+		return cmp > 0
+	})
+	return s
+}
+
+// lastUpdate returns the time of the latest event of the campaign.
+func (s CharityState) lastUpdate() time.Time {
+	last := s.UpdatedAt
+	if s.StartedAt.After(last) {
+		last = s.StartedAt
+	}
+	for _, donation := range s.Donations {
+		if donation.At.After(last) {
+			last = donation.At
+		}
+	}
+	return last
+}
+
+// CharityTracker keeps the state of the charity campaign of each
+// broadcaster. It can be snapshot with json.Marshal and restored with
+// json.Unmarshal so overlays keep their state across restarts.
+type CharityTracker struct {
+	VelocityWindow time.Duration
+	// MaxDonations is how many donations are kept in the history of a
+	// campaign. Donors are counted for every donation.
+	MaxDonations int
+
+	notifier
+
+	campaigns map[string]CharityState
+	onChange  func(state CharityState)
+	onReached func(state CharityState)
+}
+
+func NewCharityTracker() *CharityTracker {
+	return &CharityTracker{
+		VelocityWindow: DefaultVelocityWindow,
+		MaxDonations:   DefaultMaxDonations,
+		campaigns:      map[string]CharityState{},
+	}
+}
+
+func (t *CharityTracker) OnChange(callback func(state CharityState)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.onChange = callback
+}
+
+// OnReached is called once per campaign when its target is reached.
+func (t *CharityTracker) OnReached(callback func(state CharityState)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.onReached = callback
+}
+
+// Campaign returns the current or last campaign of the broadcaster.
+func (t *CharityTracker) Campaign(broadcasterID string) (CharityState, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	state, ok := t.campaigns[broadcasterID]
+	return state, ok
+}
+
+func (t *CharityTracker) MarshalJSON() ([]byte, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return json.Marshal(t.campaigns)
+}
+
+func (t *CharityTracker) UnmarshalJSON(data []byte) error {
+	campaigns := map[string]CharityState{}
+	err := json.Unmarshal(data, &campaigns)
+	if err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.campaigns = campaigns
+	return nil
+}
+
+func (t *CharityTracker) HandleStart(event EventChannelCharityCampaignStart, msg NotificationMessage) {
+	t.update(event.EventChannelCharityCampaignProgress, event.StartedAt, time.Time{}, msg)
+}
+
+func (t *CharityTracker) HandleProgress(event EventChannelCharityCampaignProgress, msg NotificationMessage) {
+	t.update(event, time.Time{}, time.Time{}, msg)
+}
+
+func (t *CharityTracker) HandleStop(event EventChannelCharityCampaignStop, msg NotificationMessage) {
+	stoppedAt := event.StoppedAt
+	if stoppedAt.IsZero() {
+		stoppedAt = msg.Metadata.MessageTimestamp
+	}
+	t.update(event.EventChannelCharityCampaignProgress, time.Time{}, stoppedAt, msg)
+}
+
+// HandleDonate adds the donation to the history and donors of the campaign.
+// The amounts are only updated by progress events, which twitch sends for
+// every donation.
+func (t *CharityTracker) HandleDonate(event EventChannelCharityCampaignDonate, msg NotificationMessage) {
+	t.mu.Lock()
+	if t.campaigns == nil {
+		t.campaigns = map[string]CharityState{}
+	}
+
+	state, ok := t.campaigns[event.BroadcasterUserId]
+	if ok && state.CampaignID != event.CampaignID && msg.Metadata.MessageTimestamp.Before(state.lastUpdate()) {
+		// a late donation of an earlier campaign
+		t.mu.Unlock()
+		return
+	}
+	if !ok || state.CampaignID != event.CampaignID {
+		state = CharityState{
+			Broadcaster:        event.Broadcaster,
+			CampaignID:         event.CampaignID,
+			CharityName:        event.CharityName,
+			CharityDescription: event.CharityDescription,
+			CharityLogo:        event.CharityLogo,
+			CharityWebsite:     event.CharityWebsite,
+		}
+	}
+
+	state = state.addDonation(CharityDonation{
+		User:   event.User,
+		ID:     event.ID,
+		Amount: event.Amount,
+		At:     msg.Metadata.MessageTimestamp,
+	}, t.MaxDonations)
+	t.campaigns[event.BroadcasterUserId] = state
+
+	t.notify(state, false)
+}
+
+func (t *CharityTracker) update(event EventChannelCharityCampaignProgress, startedAt, stoppedAt time.Time, msg NotificationMessage) {
+	campaignID, broadcaster := event.Campaign()
+	state := CharityState{
+		Broadcaster:        broadcaster,
+		CampaignID:         campaignID,
+		CharityName:        event.CharityName,
+		CharityDescription: event.CharityDescription,
+		CharityLogo:        event.CharityLogo,
+		CharityWebsite:     event.CharityWebsite,
+		CurrentAmount:      event.CurrentAmount,
+		TargetAmount:       event.TargetAmount,
+		StartedAt:          startedAt,
+		StoppedAt:          stoppedAt,
+		UpdatedAt:          msg.Metadata.MessageTimestamp,
+	}
+
+	t.mu.Lock()
+	if t.campaigns == nil {
+		t.campaigns = map[string]CharityState{}
+	}
+
+	old, ok := t.campaigns[broadcaster.BroadcasterUserId]
+	sameCampaign := ok && old.CampaignID == campaignID
+	switch {
+	case sameCampaign && isStale(old.UpdatedAt, state.UpdatedAt, old.IsStopped(), state.IsStopped()):
+		t.mu.Unlock()
+		return
+	case ok && !sameCampaign && state.UpdatedAt.Before(old.UpdatedAt):
+		t.mu.Unlock()
+		return
+	}
+
+	wasReached := false
+	if sameCampaign {
+		wasReached = old.IsReached()
+		state.Donations, state.Donors, state.Samples = old.Donations, old.Donors, old.Samples
+		if state.StartedAt.IsZero() {
+			state.StartedAt = old.StartedAt
+		}
+	}
+
+	current, _ := state.amounts()
+	state.Samples = addSample(state.Samples, ProgressSample{state.UpdatedAt, current}, t.VelocityWindow)
+	t.campaigns[broadcaster.BroadcasterUserId] = state
+
+	t.notify(state, !wasReached && state.IsReached())
+}
+
+func (t *CharityTracker) notify(state CharityState, reached bool) {
+	onChange, onReached := t.onChange, t.onReached
+	t.unlockAndNotify(func() {
+		if onChange != nil {
+			onChange(state)
+		}
+		if onReached != nil && reached {
+			onReached(state)
+		}
+	})
+}
@@ -0,0 +1,103 @@
+package twitch_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/joeyak/go-twitch-eventsub/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func usd(value int) twitch.Money {
+	return twitch.Money{Value: value, DecimalPlaces: 2, Currency: "USD"}
+}
+
+func TestCharityTracker(t *testing.T) {
+	tracker := twitch.NewCharityTracker()
+
+	var reached int
+	tracker.OnReached(func(state twitch.CharityState) { reached++ })
+
+	var start twitch.EventChannelCharityCampaignStart
+	err := json.Unmarshal([]byte(`{
+		"id": "campaign",
+		"broadcaster_id": "123456",
+		"broadcaster_login": "sunnysideup",
+		"broadcaster_name": "SunnySideUp",
+		"charity_name": "Example name",
+		"current_amount": {"value": 0, "decimal_places": 2, "currency": "USD"},
+		"target_amount": {"value": 10000, "decimal_places": 2, "currency": "USD"}
+	}`), &start)
+	assert.NoError(t, err)
+
+	now := time.Now()
+	tracker.HandleStart(start, newNotificationAt(now))
+
+	donate := func(id, user string, amount int, at time.Time) {
+		event := twitch.EventChannelCharityCampaignDonate{ID: id, Amount: usd(amount)}
+		event.CampaignID = "campaign"
+		event.BroadcasterUserId = "123456"
+		event.UserID = user
+		event.UserLogin = user
+		tracker.HandleDonate(event, newNotificationAt(at))
+	}
+	donate("1", "generous", 2500, now.Add(time.Minute))
+	donate("2", "small", 500, now.Add(time.Minute))
+	donate("3", "generous", 2500, now.Add(2*time.Minute))
+	donate("3", "generous", 2500, now.Add(2*time.Minute))
+
+	progress := start.EventChannelCharityCampaignProgress
+	progress.CurrentAmount = usd(5500)
+	tracker.HandleProgress(progress, newNotificationAt(now.Add(2*time.Minute)))
+
+	state, ok := tracker.Campaign("123456")
+	assert.True(t, ok)
+	assert.Equal(t, "campaign", state.CampaignID)
+	assert.Equal(t, "sunnysideup", state.BroadcasterUserLogin)
+	assert.Equal(t, 0.55, state.Percent())
+	assert.Len(t, state.Donations, 3)
+
+	top := state.TopDonors(1)
+	assert.Len(t, top, 1)
+	assert.Equal(t, "generous", top[0].UserLogin)
+	assert.Equal(t, "$50.00", top[0].Total.String())
+	assert.Equal(t, 2, top[0].Donations)
+
+	eta, ok := state.ETA()
+	assert.True(t, ok)
+	assert.True(t, eta.After(now.Add(2*time.Minute)))
+
+	progress.CurrentAmount = usd(10500)
+	tracker.HandleProgress(progress, newNotificationAt(now.Add(3*time.Minute)))
+	tracker.HandleStop(twitch.EventChannelCharityCampaignStop{EventChannelCharityCampaignProgress: progress}, newNotificationAt(now.Add(4*time.Minute)))
+	assert.Equal(t, 1, reached)
+
+	data, err := json.Marshal(tracker)
+	assert.NoError(t, err)
+
+	restored := twitch.NewCharityTracker()
+	assert.NoError(t, json.Unmarshal(data, restored))
+
+	state, _ = restored.Campaign("123456")
+	assert.True(t, state.IsStopped())
+	assert.Equal(t, "generous", state.TopDonors(-1)[0].UserLogin)
+
+	// a late donation of an earlier campaign doesn't replace the current one
+	late := twitch.EventChannelCharityCampaignDonate{ID: "old", Amount: usd(100)}
+	late.CampaignID = "earlier"
+	late.BroadcasterUserId = "123456"
+	tracker.HandleDonate(late, newNotificationAt(now.Add(-time.Hour)))
+
+	state, _ = tracker.Campaign("123456")
+	assert.Equal(t, "campaign", state.CampaignID)
+	assert.Len(t, state.Donations, 3)
+
+	// while a donation of a newer one does
+	late.CampaignID = "next"
+	tracker.HandleDonate(late, newNotificationAt(now.Add(time.Hour)))
+
+	state, _ = tracker.Campaign("123456")
+	assert.Equal(t, "next", state.CampaignID)
+	assert.Len(t, state.Donations, 1)
+}
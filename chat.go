@@ -0,0 +1,282 @@
+package twitch
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultChatRateLimit          = 20
+	DefaultChatModeratorRateLimit = 100
+	DefaultChatRateWindow         = 30 * time.Second
+
+	whisperSecondLimit = 3
+	whisperMinuteLimit = 100
+)
+
+// DropError is returned when twitch accepted a chat message but didn't send
+// it, for example because automod held it or the channel is in emote only
+// mode.
+type DropError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *DropError) Error() string {
+	return fmt.Sprintf("message dropped: %s: %s", e.Code, e.Message)
+}
+
+// slidingWindow counts the events of the last window.
+type slidingWindow struct {
+	times []time.Time
+}
+
+// take records an event at now, or returns how long to wait when there
+// already are limit events in the window. A limit below 1 doesn't limit.
+func (w *slidingWindow) take(now time.Time, limit int, window time.Duration) time.Duration {
+	if limit < 1 {
+		return 0
+	}
+
+	i := 0
+	for i < len(w.times) && now.Sub(w.times[i]) >= window {
+		i++
+	}
+	w.times = w.times[i:]
+
+	if len(w.times) >= limit {
+		return w.times[len(w.times)-limit].Add(window).Sub(now)
+	}
+	w.times = append(w.times, now)
+	return 0
+}
+
+// ChatSender sends chat messages, announcements, shoutouts and whispers as
+// SenderID. Chat messages wait for the chat rate limits of their channel,
+// which are higher in channels the sender moderates. The token needs the user:write:chat
+// scope, and moderator:manage:announcements, moderator:manage:shoutouts or
+// user:manage:whispers for the other calls.
+type ChatSender struct {
+	Helix    *HelixClient
+	SenderID string
+
+	// RateLimit is how many messages can be sent in RateWindow when the
+	// sender doesn't moderate the channel, and ModeratorRateLimit when it
+	// does. Verified bots can raise them.
+	RateLimit          int
+	ModeratorRateLimit int
+	RateWindow         time.Duration
+
+	mu             sync.Mutex
+	moderator      map[string]bool
+	messages       map[string]*slidingWindow
+	whisperSeconds slidingWindow
+	whisperMinutes slidingWindow
+}
+
+func NewChatSender(helix *HelixClient, senderID string) *ChatSender {
+	return &ChatSender{
+		Helix:              helix,
+		SenderID:           senderID,
+		RateLimit:          DefaultChatRateLimit,
+		ModeratorRateLimit: DefaultChatModeratorRateLimit,
+		RateWindow:         DefaultChatRateWindow,
+		moderator:          map[string]bool{},
+		messages:           map[string]*slidingWindow{},
+	}
+}
+
+// SetModerator sets if the sender moderates the channel. The sender always
+// moderates its own channel.
+func (s *ChatSender) SetModerator(broadcasterID string, isModerator bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.moderator[broadcasterID] = isModerator
+}
+
+func (s *ChatSender) IsModerator(broadcasterID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return broadcasterID == s.SenderID || s.moderator[broadcasterID]
+}
+
+// HandleModeratorAdd keeps the moderator status of the sender up to date.
+func (s *ChatSender) HandleModeratorAdd(event EventChannelModeratorAdd, msg NotificationMessage) {
+	if event.UserID == s.SenderID {
+		s.SetModerator(event.BroadcasterUserId, true)
+	}
+}
+
+func (s *ChatSender) HandleModeratorRemove(event EventChannelModeratorRemove, msg NotificationMessage) {
+	if event.UserID == s.SenderID {
+		s.SetModerator(event.BroadcasterUserId, false)
+	}
+}
+
+// Send sends the message to the broadcaster's chat and returns its ID. A
+// message twitch didn't send returns a *DropError.
+func (s *ChatSender) Send(ctx context.Context, broadcasterID, message string) (string, error) {
+	return s.send(ctx, broadcasterID, "", message)
+}
+
+// Reply sends the message as a reply to the parent message, like the
+// MessageId of an EventChannelChatMessage.
+func (s *ChatSender) Reply(ctx context.Context, broadcasterID, parentMessageID, message string) (string, error) {
+	return s.send(ctx, broadcasterID, parentMessageID, message)
+}
+
+func (s *ChatSender) send(ctx context.Context, broadcasterID, parentMessageID, message string) (string, error) {
+	if s.Helix == nil {
+		return "", fmt.Errorf("could not send chat message: %w", ErrNoHelixClient)
+	}
+
+	limit := s.RateLimit
+	if s.IsModerator(broadcasterID) {
+		limit = s.ModeratorRateLimit
+	}
+	err := s.wait(ctx, s.channelWindow(broadcasterID), limit, s.RateWindow)
+	if err != nil {
+		return "", fmt.Errorf("could not send chat message: %w", err)
+	}
+
+	body := struct {
+		BroadcasterID        string `json:"broadcaster_id"`
+		SenderID             string `json:"sender_id"`
+		Message              string `json:"message"`
+		ReplyParentMessageID string `json:"reply_parent_message_id,omitempty"`
+	}{broadcasterID, s.SenderID, message, parentMessageID}
+
+	var response struct {
+		Data []struct {
+			MessageID  string     `json:"message_id"`
+			IsSent     bool       `json:"is_sent"`
+			DropReason *DropError `json:"drop_reason"`
+		} `json:"data"`
+	}
+	err = s.Helix.do(ctx, http.MethodPost, "/chat/messages", nil, body, &response)
+	if err != nil {
+		return "", fmt.Errorf("could not send chat message: %w", err)
+	}
+
+	if len(response.Data) == 0 {
+		return "", fmt.Errorf("could not send chat message: empty response")
+	}
+
+	data := response.Data[0]
+	if !data.IsSent {
+		if data.DropReason == nil {
+			data.DropReason = &DropError{}
+		}
+		return data.MessageID, data.DropReason
+	}
+	return data.MessageID, nil
+}
+
+// Announce sends an announcement to the broadcaster's chat. An empty color
+// uses the channel's accent color.
+func (s *ChatSender) Announce(ctx context.Context, broadcasterID, message string, color AnnouncementColor) error {
+	if s.Helix == nil {
+		return fmt.Errorf("could not send announcement: %w", ErrNoHelixClient)
+	}
+
+	query := url.Values{
+		"broadcaster_id": {broadcasterID},
+		"moderator_id":   {s.SenderID},
+	}
+	body := struct {
+		Message string            `json:"message"`
+		Color   AnnouncementColor `json:"color,omitempty"`
+	}{message, color}
+
+	err := s.Helix.do(ctx, http.MethodPost, "/chat/announcements", query, body, nil)
+	if err != nil {
+		return fmt.Errorf("could not send announcement: %w", err)
+	}
+	return nil
+}
+
+// Shoutout shouts out the other broadcaster in the chat of the first one.
+// Twitch allows one shoutout every 2 minutes, and one of the same
+// broadcaster every hour.
+func (s *ChatSender) Shoutout(ctx context.Context, fromBroadcasterID, toBroadcasterID string) error {
+	if s.Helix == nil {
+		return fmt.Errorf("could not send shoutout: %w", ErrNoHelixClient)
+	}
+
+	query := url.Values{
+		"from_broadcaster_id": {fromBroadcasterID},
+		"to_broadcaster_id":   {toBroadcasterID},
+		"moderator_id":        {s.SenderID},
+	}
+
+	err := s.Helix.do(ctx, http.MethodPost, "/chat/shoutouts", query, nil, nil)
+	if err != nil {
+		return fmt.Errorf("could not send shoutout: %w", err)
+	}
+	return nil
+}
+
+// Whisper sends a whisper to the user, waiting for the whisper rate limits.
+// The sender needs a verified phone number.
+func (s *ChatSender) Whisper(ctx context.Context, toUserID, message string) error {
+	if s.Helix == nil {
+		return fmt.Errorf("could not send whisper: %w", ErrNoHelixClient)
+	}
+
+	err := s.wait(ctx, &s.whisperSeconds, whisperSecondLimit, time.Second)
+	if err == nil {
+		err = s.wait(ctx, &s.whisperMinutes, whisperMinuteLimit, time.Minute)
+	}
+	if err != nil {
+		return fmt.Errorf("could not send whisper: %w", err)
+	}
+
+	query := url.Values{
+		"from_user_id": {s.SenderID},
+		"to_user_id":   {toUserID},
+	}
+	body := struct {
+		Message string `json:"message"`
+	}{message}
+
+	err = s.Helix.do(ctx, http.MethodPost, "/whispers", query, body, nil)
+	if err != nil {
+		return fmt.Errorf("could not send whisper: %w", err)
+	}
+	return nil
+}
+
+// channelWindow returns the window of the chat messages sent to the
+// broadcaster's channel, since the limits apply to each channel.
+func (s *ChatSender) channelWindow(broadcasterID string) *slidingWindow {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	window, ok := s.messages[broadcasterID]
+	if !ok {
+		window = &slidingWindow{}
+		s.messages[broadcasterID] = window
+	}
+	return window
+}
+
+func (s *ChatSender) wait(ctx context.Context, window *slidingWindow, limit int, duration time.Duration) error {
+	for {
+		s.mu.Lock()
+		wait := window.take(time.Now(), limit, duration)
+		s.mu.Unlock()
+
+		if wait <= 0 {
+			return nil
+		}
+
+		err := sleep(ctx, wait)
+		if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package twitch_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/joeyak/go-twitch-eventsub/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+type chatRequest struct {
+	Path  string
+	Query string
+	Body  map[string]string
+}
+
+func newChatServer(t *testing.T) (*twitch.HelixClient, func() []chatRequest) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var mu sync.Mutex
+	var requests []chatRequest
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		body := map[string]string{}
+		json.Unmarshal(data, &body)
+
+		mu.Lock()
+		requests = append(requests, chatRequest{r.URL.Path, r.URL.RawQuery, body})
+		mu.Unlock()
+
+		if r.URL.Path != "/chat/messages" {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		if body["message"] == "dropped" {
+			fmt.Fprint(w, `{"data":[{"message_id":"","is_sent":false,"drop_reason":{"code":"msg_duplicate","message":"duplicate message"}}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"data":[{"message_id":"sent","is_sent":true}]}`)
+	})
+	go http.Serve(listener, mux)
+
+	helix := twitch.NewHelixClientWithUrl(fmt.Sprintf("http://%s", listener.Addr()), "client", "token")
+	helix.RateLimiter = newTestRateLimiter()
+	return helix, func() []chatRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]chatRequest(nil), requests...)
+	}
+}
+
+func TestChatSenderSend(t *testing.T) {
+	t.Parallel()
+
+	helix, requests := newChatServer(t)
+	sender := twitch.NewChatSender(helix, "bot")
+	ctx := context.Background()
+
+	id, err := sender.Reply(ctx, "1337", "parent", "hello")
+	assert.NoError(t, err)
+	assert.Equal(t, "sent", id)
+
+	_, err = sender.Send(ctx, "1337", "dropped")
+	var dropErr *twitch.DropError
+	if assert.True(t, errors.As(err, &dropErr)) {
+		assert.Equal(t, "msg_duplicate", dropErr.Code)
+	}
+
+	err = sender.Announce(ctx, "1337", "announcement", twitch.AnnouncementColorPurple)
+	assert.NoError(t, err)
+
+	err = sender.Whisper(ctx, "42", "psst")
+	assert.NoError(t, err)
+
+	assert.Equal(t, []chatRequest{
+		{"/chat/messages", "", map[string]string{"broadcaster_id": "1337", "sender_id": "bot", "message": "hello", "reply_parent_message_id": "parent"}},
+		{"/chat/messages", "", map[string]string{"broadcaster_id": "1337", "sender_id": "bot", "message": "dropped"}},
+		{"/chat/announcements", "broadcaster_id=1337&moderator_id=bot", map[string]string{"message": "announcement", "color": "purple"}},
+		{"/whispers", "from_user_id=bot&to_user_id=42", map[string]string{"message": "psst"}},
+	}, requests())
+}
+
+func TestChatSenderRateLimit(t *testing.T) {
+	t.Parallel()
+
+	helix, requests := newChatServer(t)
+	sender := twitch.NewChatSender(helix, "bot")
+	sender.RateLimit = 1
+	sender.ModeratorRateLimit = 2
+	sender.RateWindow = 100 * time.Millisecond
+	sender.HandleModeratorAdd(twitch.EventChannelModeratorAdd{
+		Broadcaster: twitch.Broadcaster{BroadcasterUserId: "mod"},
+		User:        twitch.User{UserID: "bot"},
+	}, newNotificationAt(time.Now()))
+	assert.True(t, sender.IsModerator("mod"))
+
+	ctx := context.Background()
+	start := time.Now()
+	sender.Send(ctx, "1337", "one")
+	sender.Send(ctx, "mod", "one")
+	sender.Send(ctx, "mod", "two")
+	// each channel has its own window
+	assert.Less(t, time.Since(start), sender.RateWindow)
+
+	// the channel isn't moderated so the limit is already reached
+	timeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	_, err := sender.Send(timeout, "1337", "two")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	_, err = sender.Send(ctx, "1337", "two")
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), sender.RateWindow)
+	assert.Len(t, requests(), 4)
+}
@@ -0,0 +1,51 @@
+package twitch
+
+import (
+	"net"
+	"net/url"
+)
+
+// The websocket server started by `twitch event websocket start-server`
+// and its subscription endpoint.
+const (
+	TwitchCLIWebsocketUrl    = "ws://127.0.0.1:8080/ws"
+	TwitchCLISubscriptionUrl = "http://127.0.0.1:8080/eventsub/subscriptions"
+)
+
+const localSubscriptionPath = "/eventsub/subscriptions"
+
+// NewCLIClient returns a client for the twitch CLI's websocket server. Run
+// `twitch event websocket start-server` first, then trigger events with
+// `twitch event trigger <event> --transport=websocket`.
+func NewCLIClient() *Client {
+	return NewClientWithUrl(TwitchCLIWebsocketUrl)
+}
+
+// subscriptionUrlFor returns the subscription endpoint that goes with the
+// websocket url. Local servers like the twitch CLI serve it next to the
+// websocket, everything else uses twitch's.
+func subscriptionUrlFor(address string) string {
+	u, err := url.Parse(address)
+	if err != nil || !isLoopback(u.Hostname()) {
+		return twitchEventSubUrl
+	}
+
+	switch u.Scheme {
+	case "ws":
+		u.Scheme = "http"
+	case "wss":
+		u.Scheme = "https"
+	}
+	u.Path = localSubscriptionPath
+	u.RawQuery = ""
+	u.Fragment = ""
+	return u.String()
+}
+
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,30 @@
+package twitch_test
+
+import (
+	"testing"
+
+	"github.com/joeyak/go-twitch-eventsub/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientSubscriptionUrl(t *testing.T) {
+	testCases := []struct {
+		Address  string
+		Expected string
+	}{
+		{"wss://eventsub.wss.twitch.tv/ws", "https://api.twitch.tv/helix/eventsub/subscriptions"},
+		{"wss://eventsub.wss.twitch.tv/ws?keepalive_timeout_seconds=30", "https://api.twitch.tv/helix/eventsub/subscriptions"},
+		{twitch.TwitchCLIWebsocketUrl, twitch.TwitchCLISubscriptionUrl},
+		{"ws://localhost:8080/ws?reconnect=abc", "http://localhost:8080/eventsub/subscriptions"},
+		{"wss://[::1]:443/ws", "https://[::1]:443/eventsub/subscriptions"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Address, func(t *testing.T) {
+			assert.Equal(t, tc.Expected, twitch.NewClientWithUrl(tc.Address).SubscriptionUrl)
+			assert.Equal(t, tc.Expected, twitch.NewPoolWithUrl(tc.Address, 1).SubscriptionUrl)
+		})
+	}
+
+	assert.Equal(t, twitch.TwitchCLIWebsocketUrl, twitch.NewCLIClient().Address)
+}
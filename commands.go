@@ -0,0 +1,320 @@
+package twitch
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+const DefaultCommandPrefix = "!"
+
+var ErrCommandPermission = fmt.Errorf("chatter is not allowed to use the command")
+
+type CooldownError struct {
+	// IsGlobal is set when the command is on cooldown for every chatter.
+	IsGlobal  bool
+	Remaining time.Duration
+}
+
+func (e *CooldownError) Error() string {
+	scope := "user"
+	if e.IsGlobal {
+		scope = "global"
+	}
+	return fmt.Sprintf("command is on %s cooldown for %s", scope, e.Remaining)
+}
+
+// CommandArg is an argument of a command. Mentions are always their own
+// argument unless they're inside quotes.
+type CommandArg struct {
+	Text    string
+	Mention *ChatMessageFragmentMention
+}
+
+// CommandContext is passed to command handlers.
+type CommandContext struct {
+	Event   EventChannelChatMessage
+	Message NotificationMessage
+
+	// Command is the registered name of the command and Alias the name the
+	// chatter used.
+	Command string
+	Alias   string
+	Args    []CommandArg
+	// RawArgs is the text after the command name.
+	RawArgs string
+}
+
+// Arg returns the text of the i-th argument, or an empty string.
+func (c *CommandContext) Arg(i int) string {
+	if i < 0 || i >= len(c.Args) {
+		return ""
+	}
+	return c.Args[i].Text
+}
+
+func (c *CommandContext) Mentions() []ChatMessageFragmentMention {
+	var mentions []ChatMessageFragmentMention
+	for _, arg := range c.Args {
+		if arg.Mention != nil {
+			mentions = append(mentions, *arg.Mention)
+		}
+	}
+	return mentions
+}
+
+// ReplyParentID returns the message ID to send a reply to the command with.
+// Twitch puts the reply in the thread of the command when it was sent in
+// one.
+func (c *CommandContext) ReplyParentID() string {
+	return c.Event.MessageId
+}
+
+// Parent returns the message the command was sent as a reply to, like a
+// !quote replying to the message to quote.
+func (c *CommandContext) Parent() (ChatMessageReply, bool) {
+	if c.Event.Reply == nil {
+		return ChatMessageReply{}, false
+	}
+	return *c.Event.Reply, true
+}
+
+type Command struct {
+	Name    string
+	Aliases []string
+	// Permission is the lowest level allowed to use the command.
+	Permission PermissionLevel
+	// UserCooldown is how long a chatter waits between uses and
+	// GlobalCooldown how long everyone waits. They're kept per channel.
+	UserCooldown   time.Duration
+	GlobalCooldown time.Duration
+	Handler        func(ctx *CommandContext) error
+}
+
+// CommandRouter parses chat messages starting with Prefix and calls the
+// handler of the command. Names and aliases are matched case insensitively,
+// and the mention twitch adds in front of replies is skipped.
+type CommandRouter struct {
+	Prefix string
+
+	mu       sync.Mutex
+	commands map[string]*Command
+	used     map[string]time.Time
+	onDenied func(ctx *CommandContext, err error)
+	onError  func(err error)
+}
+
+func NewCommandRouter() *CommandRouter {
+	return &CommandRouter{
+		Prefix:   DefaultCommandPrefix,
+		commands: map[string]*Command{},
+		used:     map[string]time.Time{},
+		onError:  func(err error) { fmt.Printf("ERROR: %v\n", err) },
+	}
+}
+
+// OnDenied is called when a command isn't run, with ErrCommandPermission or
+// a *CooldownError.
+func (r *CommandRouter) OnDenied(callback func(ctx *CommandContext, err error)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.onDenied = callback
+}
+
+// OnError is called with the errors returned by handlers.
+func (r *CommandRouter) OnError(callback func(err error)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.onError = callback
+}
+
+// Register adds the command. It fails when the name or an alias is already
+// used by another command.
+func (r *CommandRouter) Register(command Command) error {
+	if command.Name == "" || command.Handler == nil {
+		return fmt.Errorf("could not register command %q: missing name or handler", command.Name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	names := append([]string{command.Name}, command.Aliases...)
+	for _, name := range names {
+		if _, ok := r.commands[strings.ToLower(name)]; ok {
+			return fmt.Errorf("could not register command %q: %q is already registered", command.Name, name)
+		}
+	}
+	for _, name := range names {
+		r.commands[strings.ToLower(name)] = &command
+	}
+	return nil
+}
+
+// Handle registers a command without aliases, permissions or cooldowns.
+func (r *CommandRouter) Handle(name string, handler func(ctx *CommandContext) error) error {
+	return r.Register(Command{Name: name, Handler: handler})
+}
+
+// Unregister removes the command and its aliases.
+func (r *CommandRouter) Unregister(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	command, ok := r.commands[strings.ToLower(name)]
+	if !ok {
+		return
+	}
+	for key, registered := range r.commands {
+		if registered == command {
+			delete(r.commands, key)
+		}
+	}
+}
+
+func (r *CommandRouter) HandleChatMessage(event EventChannelChatMessage, msg NotificationMessage) {
+	text, args := parseCommandMessage(event)
+	if len(args) == 0 || args[0].Mention != nil || !strings.HasPrefix(args[0].Text, r.Prefix) {
+		return
+	}
+
+	alias := strings.TrimPrefix(args[0].Text, r.Prefix)
+	r.mu.Lock()
+	command, ok := r.commands[strings.ToLower(alias)]
+	r.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	ctx := &CommandContext{
+		Event:   event,
+		Message: msg,
+		Command: command.Name,
+		Alias:   alias,
+		Args:    args[1:],
+	}
+	if i := strings.IndexFunc(text, unicode.IsSpace); i >= 0 {
+		ctx.RawArgs = strings.TrimSpace(text[i:])
+	}
+
+	if event.PermissionLevel() < command.Permission {
+		r.deny(ctx, ErrCommandPermission)
+		return
+	}
+
+	err := r.cooldown(command, event, msg)
+	if err != nil {
+		r.deny(ctx, err)
+		return
+	}
+
+	err = command.Handler(ctx)
+	if err != nil {
+		r.error(fmt.Errorf("could not run command %s: %w", command.Name, err))
+	}
+}
+
+// cooldown starts the cooldowns of the command, or returns a *CooldownError
+// when one is still running.
+func (r *CommandRouter) cooldown(command *Command, event EventChannelChatMessage, msg NotificationMessage) error {
+	now := msg.Metadata.MessageTimestamp
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	globalKey := event.BroadcasterUserId + ":" + command.Name
+	userKey := globalKey + ":" + event.ChatterUserId
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if remaining := command.GlobalCooldown - now.Sub(r.used[globalKey]); remaining > 0 {
+		return &CooldownError{IsGlobal: true, Remaining: remaining}
+	}
+	if remaining := command.UserCooldown - now.Sub(r.used[userKey]); remaining > 0 {
+		return &CooldownError{Remaining: remaining}
+	}
+
+	if command.GlobalCooldown > 0 {
+		r.used[globalKey] = now
+	}
+	if command.UserCooldown > 0 {
+		r.used[userKey] = now
+	}
+	return nil
+}
+
+func (r *CommandRouter) deny(ctx *CommandContext, err error) {
+	r.mu.Lock()
+	onDenied := r.onDenied
+	r.mu.Unlock()
+
+	if onDenied != nil {
+		onDenied(ctx, err)
+	}
+}
+
+func (r *CommandRouter) error(err error) {
+	r.mu.Lock()
+	onError := r.onError
+	r.mu.Unlock()
+
+	if onError != nil {
+		onError(err)
+	}
+}
+
+// parseCommandMessage returns the text of the message without the reply
+// mention, and its arguments split on whitespace outside of quotes.
+func parseCommandMessage(event EventChannelChatMessage) (string, []CommandArg) {
+	fragments := event.Message.Fragments
+	if len(fragments) == 0 {
+		fragments = []ChatMessageFragment{{Type: "text", Text: event.Message.Text}}
+	}
+
+	// replies start with a mention of the parent's author
+	if event.Reply != nil && len(fragments) > 0 && fragments[0].Type == "mention" {
+		fragments = fragments[1:]
+	}
+
+	var text strings.Builder
+	for _, fragment := range fragments {
+		text.WriteString(fragment.Text)
+	}
+
+	var args []CommandArg
+	var current strings.Builder
+	inQuote, inArg := false, false
+	flush := func() {
+		if inArg {
+			args = append(args, CommandArg{Text: current.String()})
+		}
+		current.Reset()
+		inArg = false
+	}
+
+	for _, fragment := range fragments {
+		if fragment.Type == "mention" && fragment.Mention != nil && !inQuote {
+			flush()
+			args = append(args, CommandArg{Text: fragment.Text, Mention: fragment.Mention})
+			continue
+		}
+
+		for _, r := range fragment.Text {
+			switch {
+			case r == '"':
+				inQuote = !inQuote
+				inArg = true
+			case unicode.IsSpace(r) && !inQuote:
+				flush()
+			default:
+				current.WriteRune(r)
+				inArg = true
+			}
+		}
+	}
+	flush()
+
+	return strings.TrimSpace(text.String()), args
+}
@@ -0,0 +1,128 @@
+package twitch_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/joeyak/go-twitch-eventsub/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func newCommandMessage(chatterID string, badges twitch.Badges, fragments ...twitch.ChatMessageFragment) twitch.EventChannelChatMessage {
+	event := twitch.EventChannelChatMessage{
+		Broadcaster: twitch.Broadcaster{BroadcasterUserId: "1337"},
+		Chatter:     twitch.Chatter{ChatterUserId: chatterID},
+		MessageId:   "message",
+		Badges:      badges,
+	}
+	for _, fragment := range fragments {
+		event.Message.Text += fragment.Text
+	}
+	event.Message.Fragments = fragments
+	return event
+}
+
+func TestCommandRouterArgs(t *testing.T) {
+	router := twitch.NewCommandRouter()
+
+	var ctx *twitch.CommandContext
+	err := router.Register(twitch.Command{
+		Name:    "shoutout",
+		Aliases: []string{"so"},
+		Handler: func(c *twitch.CommandContext) error {
+			ctx = c
+			return nil
+		},
+	})
+	assert.NoError(t, err)
+
+	mention := &twitch.ChatMessageFragmentMention{UserID: "42", UserLogin: "friend"}
+	event := newCommandMessage("7", nil,
+		twitch.ChatMessageFragment{Type: "text", Text: "!SO "},
+		twitch.ChatMessageFragment{Type: "mention", Text: "@friend", Mention: mention},
+		twitch.ChatMessageFragment{Type: "text", Text: ` "great stream" today`},
+	)
+	router.HandleChatMessage(event, newNotificationAt(time.Now()))
+
+	if assert.NotNil(t, ctx) {
+		assert.Equal(t, "shoutout", ctx.Command)
+		assert.Equal(t, "SO", ctx.Alias)
+		assert.Equal(t, `@friend "great stream" today`, ctx.RawArgs)
+		assert.Equal(t, []twitch.CommandArg{
+			{Text: "@friend", Mention: mention},
+			{Text: "great stream"},
+			{Text: "today"},
+		}, ctx.Args)
+		assert.Equal(t, []twitch.ChatMessageFragmentMention{*mention}, ctx.Mentions())
+		assert.Equal(t, "message", ctx.ReplyParentID())
+	}
+
+	err = router.Handle("so", func(c *twitch.CommandContext) error { return nil })
+	assert.Error(t, err)
+}
+
+func TestCommandRouterReply(t *testing.T) {
+	router := twitch.NewCommandRouter()
+
+	var ctx *twitch.CommandContext
+	router.Handle("quote", func(c *twitch.CommandContext) error {
+		ctx = c
+		return nil
+	})
+
+	event := newCommandMessage("7", nil,
+		twitch.ChatMessageFragment{Type: "mention", Text: "@friend", Mention: &twitch.ChatMessageFragmentMention{UserID: "42"}},
+		twitch.ChatMessageFragment{Type: "text", Text: " !quote"},
+	)
+	event.Reply = &twitch.ChatMessageReply{ParentMessageId: "parent", ParentMessageBody: "quote me"}
+	router.HandleChatMessage(event, newNotificationAt(time.Now()))
+
+	if assert.NotNil(t, ctx) {
+		assert.Empty(t, ctx.Args)
+		parent, ok := ctx.Parent()
+		assert.True(t, ok)
+		assert.Equal(t, "quote me", parent.ParentMessageBody)
+	}
+}
+
+func TestCommandRouterDenied(t *testing.T) {
+	router := twitch.NewCommandRouter()
+
+	var denied []error
+	router.OnDenied(func(ctx *twitch.CommandContext, err error) { denied = append(denied, err) })
+
+	var errs []error
+	router.OnError(func(err error) { errs = append(errs, err) })
+
+	runs := 0
+	router.Register(twitch.Command{
+		Name:           "clip",
+		Permission:     twitch.PermissionVIP,
+		UserCooldown:   time.Minute,
+		GlobalCooldown: 10 * time.Second,
+		Handler: func(ctx *twitch.CommandContext) error {
+			runs++
+			return fmt.Errorf("clip failed")
+		},
+	})
+
+	now := time.Now()
+	vip := twitch.Badges{{SetId: twitch.BadgeVIP}}
+	text := twitch.ChatMessageFragment{Type: "text", Text: "!clip"}
+
+	router.HandleChatMessage(newCommandMessage("7", nil, text), newNotificationAt(now))
+	router.HandleChatMessage(newCommandMessage("7", vip, text), newNotificationAt(now))
+	router.HandleChatMessage(newCommandMessage("8", vip, text), newNotificationAt(now.Add(5*time.Second)))
+	router.HandleChatMessage(newCommandMessage("7", vip, text), newNotificationAt(now.Add(20*time.Second)))
+	router.HandleChatMessage(newCommandMessage("8", vip, text), newNotificationAt(now.Add(20*time.Second)))
+	router.HandleChatMessage(newCommandMessage("1337", nil, twitch.ChatMessageFragment{Type: "text", Text: "clip"}), newNotificationAt(now.Add(time.Hour)))
+
+	assert.Equal(t, 2, runs)
+	assert.Len(t, errs, 2)
+	if assert.Len(t, denied, 3) {
+		assert.ErrorIs(t, denied[0], twitch.ErrCommandPermission)
+		assert.Equal(t, &twitch.CooldownError{IsGlobal: true, Remaining: 5 * time.Second}, denied[1])
+		assert.Equal(t, &twitch.CooldownError{Remaining: 40 * time.Second}, denied[2])
+	}
+}
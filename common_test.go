@@ -223,3 +223,7 @@ func connect(t *testing.T, client *twitch.Client) {
 		t.Errorf("could not connect client: %v", err)
 	}
 }
+
+func newNotificationAt(timestamp time.Time) twitch.NotificationMessage {
+	return twitch.NotificationMessage{Metadata: twitch.MessageMetadata{MessageType: "notification", MessageTimestamp: timestamp}}
+}
@@ -6,6 +6,8 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/coder/websocket"
 )
@@ -45,15 +47,28 @@ func callFuncWithMsg[T any](f func(T, NotificationMessage), v T, msg Notificatio
 	}
 }
 
-type Client struct {
-	Address    string
-	ws         *websocket.Conn
-	connected  bool
-	ctx        context.Context
-	readLoopWG sync.WaitGroup
+func callFuncWithEvent[T, E any](f func(T, E, NotificationMessage), v T, event E, msg NotificationMessage) {
+	if f != nil {
+		go f(v, event, msg)
+	}
+}
 
-	reconnecting bool
-	reconnected  chan struct{}
+type Client struct {
+	Address         string
+	SubscriptionUrl string
+	RateLimiter     *RateLimiter
+	ws              *websocket.Conn
+	connected       bool
+	ctx             context.Context
+	readLoopWG      sync.WaitGroup
+
+	mu        sync.Mutex
+	sessionID string
+	budget    *SubscriptionBudget
+	recorder  *Recorder
+
+	reconnecting atomic.Bool
+	reconnected  chan reconnection
 
 	// Responses
 	onError        func(err error)
@@ -63,6 +78,13 @@ type Client struct {
 	onReconnect    func(message ReconnectMessage)
 	onRevoke       func(message RevokeMessage)
 
+	// Revocation reasons
+	onRevokeAuthorizationRevoked         func(message RevokeMessage)
+	onRevokeUserRemoved                  func(message RevokeMessage)
+	onRevokeVersionRemoved               func(message RevokeMessage)
+	onRevokeNotificationFailuresExceeded func(message RevokeMessage)
+	onRevokeModeratorRemoved             func(message RevokeMessage)
+
 	// Events
 	onRawEvent                                              func(event string, metadata MessageMetadata, subscription PayloadSubscription)
 	onEventChannelUpdate                                    func(event EventChannelUpdate, msg NotificationMessage)
@@ -138,17 +160,56 @@ type Client struct {
 	onEventChannelUnbanRequestCreate                        func(event EventChannelUnbanRequestCreate, msg NotificationMessage)
 	onEventChannelUnbanRequestResolve                       func(event EventChannelUnbanRequestResolve, msg NotificationMessage)
 	onEventConduitShardDisabled                             func(event EventConduitShardDisabled, msg NotificationMessage)
+
+	// Chat notices
+	onChatNoticeSub              func(notice NoticeSub, event EventChannelChatNotification, msg NotificationMessage)
+	onChatNoticeResub            func(notice NoticeResub, event EventChannelChatNotification, msg NotificationMessage)
+	onChatNoticeSubGift          func(notice NoticeSubGift, event EventChannelChatNotification, msg NotificationMessage)
+	onChatNoticeCommunitySubGift func(notice NoticeCommunitySubGift, event EventChannelChatNotification, msg NotificationMessage)
+	onChatNoticeGiftPaidUpgrade  func(notice NoticeGiftPaidUpgrade, event EventChannelChatNotification, msg NotificationMessage)
+	onChatNoticePrimePaidUpgrade func(notice NoticePrimePaidUpgrade, event EventChannelChatNotification, msg NotificationMessage)
+	onChatNoticePayItForward     func(notice NoticePayItForward, event EventChannelChatNotification, msg NotificationMessage)
+	onChatNoticeRaid             func(notice NoticeRaid, event EventChannelChatNotification, msg NotificationMessage)
+	onChatNoticeUnraid           func(notice NoticeUnraid, event EventChannelChatNotification, msg NotificationMessage)
+	onChatNoticeAnnouncement     func(notice NoticeAnnouncement, event EventChannelChatNotification, msg NotificationMessage)
+	onChatNoticeBitsBadgeTier    func(notice NoticeBitsBadgeTier, event EventChannelChatNotification, msg NotificationMessage)
+	onChatNoticeCharityDonation  func(notice NoticeCharityDonation, event EventChannelChatNotification, msg NotificationMessage)
+
+	// Moderate actions
+	onModerateBan          func(detail ModerateBan, event EventChannelModerate, msg NotificationMessage)
+	onModerateTimeout      func(detail ModerateTimeout, event EventChannelModerate, msg NotificationMessage)
+	onModerateUnban        func(detail ModerateUnban, event EventChannelModerate, msg NotificationMessage)
+	onModerateUntimeout    func(detail ModerateUntimeout, event EventChannelModerate, msg NotificationMessage)
+	onModerateDelete       func(detail ModerateDelete, event EventChannelModerate, msg NotificationMessage)
+	onModerateVip          func(detail ModerateVip, event EventChannelModerate, msg NotificationMessage)
+	onModerateUnvip        func(detail ModerateUnvip, event EventChannelModerate, msg NotificationMessage)
+	onModerateMod          func(detail ModerateMod, event EventChannelModerate, msg NotificationMessage)
+	onModerateUnmod        func(detail ModerateUnmod, event EventChannelModerate, msg NotificationMessage)
+	onModerateRaid         func(detail ModerateRaid, event EventChannelModerate, msg NotificationMessage)
+	onModerateUnraid       func(detail ModerateUnraid, event EventChannelModerate, msg NotificationMessage)
+	onModerateFollowers    func(detail ModerateFollowers, event EventChannelModerate, msg NotificationMessage)
+	onModerateSlow         func(detail ModerateSlow, event EventChannelModerate, msg NotificationMessage)
+	onModerateWarn         func(detail ModerateWarn, event EventChannelModerate, msg NotificationMessage)
+	onModerateAutomodTerms func(detail ModerateAutomodTerms, event EventChannelModerate, msg NotificationMessage)
+	onModerateUnbanRequest func(detail ModerateUnbanRequest, event EventChannelModerate, msg NotificationMessage)
+	onModerateChatMode     func(detail ModerateChatMode, event EventChannelModerate, msg NotificationMessage)
+	onModerateClear        func(detail ModerateClear, event EventChannelModerate, msg NotificationMessage)
 }
 
 func NewClient() *Client {
 	return NewClientWithUrl(twitchWebsocketUrl)
 }
 
+// NewClientWithUrl connects to the websocket url. Clients of a local server,
+// like the twitch CLI, subscribe through its /eventsub/subscriptions.
 func NewClientWithUrl(url string) *Client {
 	return &Client{
-		Address:     url,
-		reconnected: make(chan struct{}),
-		onError:     func(err error) { fmt.Printf("ERROR: %v\n", err) },
+		Address:         url,
+		SubscriptionUrl: subscriptionUrlFor(url),
+		RateLimiter:     DefaultRateLimiter,
+		budget:          NewSubscriptionBudget(),
+		reconnected:     make(chan reconnection),
+		onError:         func(err error) { fmt.Printf("ERROR: %v\n", err) },
 	}
 }
 
@@ -177,6 +238,7 @@ func (c *Client) ConnectWithContext(
 	c.readLoopWG.Add(1)
 	go func() {
 		defer c.readLoopWG.Done()
+		defer func() { c.Budget().RemoveSession(c.SessionID()) }()
 		c.readLoop(ctx, onReadError)
 	}()
 	return nil
@@ -186,6 +248,46 @@ func (c *Client) Wait() {
 	c.readLoopWG.Wait()
 }
 
+func (c *Client) SessionID() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.sessionID
+}
+
+func (c *Client) Budget() *SubscriptionBudget {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.budget
+}
+
+// SetBudget shares a budget between clients using the same token.
+func (c *Client) SetBudget(budget *SubscriptionBudget) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.budget = budget
+}
+
+// Subscribe subscribes the current session to an event through the client's
+// budget and rate limiter. The session ID is filled in when the request
+// doesn't have one.
+func (c *Client) Subscribe(ctx context.Context, request SubscribeRequest) (SubscribeResponse, error) {
+	return c.subscribe(ctx, request, c.rateLimiter().MaxRetries)
+}
+
+func (c *Client) subscribe(ctx context.Context, request SubscribeRequest, maxRetries int) (SubscribeResponse, error) {
+	if request.SessionID == "" {
+		request.SessionID = c.SessionID()
+	}
+	return c.Budget().subscribe(ctx, request, c.SubscriptionUrl, c.rateLimiter(), maxRetries)
+}
+
+func (c *Client) rateLimiter() *RateLimiter {
+	if c.RateLimiter == nil {
+		return DefaultRateLimiter
+	}
+	return c.RateLimiter
+}
+
 func (c *Client) readLoop(
 	ctx context.Context,
 	onReadError func(context.Context, error),
@@ -197,24 +299,26 @@ func (c *Client) readLoop(
 				return
 			}
 
-			if websocket.CloseStatus(err) == websocket.StatusNormalClosure {
-				if c.reconnecting {
-					c.reconnecting = false
-					<-c.reconnected
-					continue
-				}
+			// the old connection was closed by reconnect, which can end the
+			// read with another error than the normal closure
+			if c.reconnecting.CompareAndSwap(true, false) {
+				next := <-c.reconnected
+				c.ws = next.ws
+				data = next.welcome
+			} else if websocket.CloseStatus(err) == websocket.StatusNormalClosure {
 				return
-			}
-
-			if onReadError == nil {
+			} else if onReadError == nil {
 				c.onError(err)
 				return
+			} else {
+				onReadError(ctx, err)
+				return
 			}
-			onReadError(ctx, err)
-			return
 		}
 
-		err = c.handleMessage(data)
+		c.record(time.Now(), data)
+
+		err = c.handleMessage(data, false)
 		if err != nil {
 			c.onError(err)
 		}
@@ -237,7 +341,9 @@ func (c *Client) Close() error {
 	return nil
 }
 
-func (c *Client) handleMessage(data []byte) error {
+// handleMessage calls the callbacks of the message. Replayed messages don't
+// change the session, the budget or the connection of the client.
+func (c *Client) handleMessage(data []byte, replayed bool) error {
 	metadata, err := parseBaseMessage(data)
 	if err != nil {
 		return err
@@ -257,6 +363,18 @@ func (c *Client) handleMessage(data []byte) error {
 
 	switch msg := message.(type) {
 	case *WelcomeMessage:
+		if !replayed {
+			c.mu.Lock()
+			previous := c.sessionID
+			c.sessionID = msg.Payload.Session.ID
+			budget := c.budget
+			c.mu.Unlock()
+
+			if previous != "" && previous != msg.Payload.Session.ID {
+				budget.moveSession(previous, msg.Payload.Session.ID)
+			}
+		}
+
 		callFunc(c.onWelcome, *msg)
 	case *KeepAliveMessage:
 		callFunc(c.onKeepAlive, *msg)
@@ -269,13 +387,21 @@ func (c *Client) handleMessage(data []byte) error {
 		}
 	case *ReconnectMessage:
 		callFunc(c.onReconnect, *msg)
+		if replayed {
+			break
+		}
 
 		err = c.reconnect(*msg)
 		if err != nil {
 			return fmt.Errorf("could not handle reconnect: %w", err)
 		}
 	case *RevokeMessage:
+		if !replayed {
+			c.Budget().Remove(msg.Payload.Subscription.ID)
+		}
+
 		callFunc(c.onRevoke, *msg)
+		c.handleRevoke(*msg)
 	default:
 		return fmt.Errorf("unhandled %T message: %v", msg, msg)
 	}
@@ -283,6 +409,13 @@ func (c *Client) handleMessage(data []byte) error {
 	return nil
 }
 
+// reconnection is the connection a reconnect switches to and its welcome,
+// which the read loop handles like any other message.
+type reconnection struct {
+	ws      *websocket.Conn
+	welcome []byte
+}
+
 func (c *Client) reconnect(message ReconnectMessage) error {
 	c.Address = message.Payload.Session.ReconnectUrl
 	ws, err := c.dial()
@@ -290,15 +423,18 @@ func (c *Client) reconnect(message ReconnectMessage) error {
 		return fmt.Errorf("could not dial to reconnect")
 	}
 
+	old := c.ws
 	go func() {
 		_, data, err := ws.Read(c.ctx)
 		if err != nil {
 			c.onError(fmt.Errorf("reconnect failed: could not read reconnect websocket for welcome: %w", err))
+			return
 		}
 
 		metadata, err := parseBaseMessage(data)
 		if err != nil {
 			c.onError(fmt.Errorf("reconnect failed: could parse base message: %w", err))
+			return
 		}
 
 		if metadata.MessageType != "session_welcome" {
@@ -306,15 +442,99 @@ func (c *Client) reconnect(message ReconnectMessage) error {
 			return
 		}
 
-		c.reconnecting = true
-		c.ws.Close(websocket.StatusNormalClosure, "Stopping Connection")
-		c.ws = ws
-		c.reconnected <- struct{}{}
+		c.reconnecting.Store(true)
+		old.Close(websocket.StatusNormalClosure, "Stopping Connection")
+		c.reconnected <- reconnection{ws, data}
 	}()
 
 	return nil
 }
 
+func (c *Client) handleRevoke(message RevokeMessage) {
+	switch message.Payload.Subscription.SubscriptionStatus() {
+	case SubscriptionStatusAuthorizationRevoked:
+		callFunc(c.onRevokeAuthorizationRevoked, message)
+	case SubscriptionStatusUserRemoved:
+		callFunc(c.onRevokeUserRemoved, message)
+	case SubscriptionStatusVersionRemoved:
+		callFunc(c.onRevokeVersionRemoved, message)
+	case SubscriptionStatusNotificationFailuresExceeded:
+		callFunc(c.onRevokeNotificationFailuresExceeded, message)
+	case SubscriptionStatusModeratorRemoved:
+		callFunc(c.onRevokeModeratorRemoved, message)
+	}
+}
+
+func (c *Client) handleChatNotice(event EventChannelChatNotification, msg NotificationMessage) {
+	switch notice := event.Notice().(type) {
+	case NoticeSub:
+		callFuncWithEvent(c.onChatNoticeSub, notice, event, msg)
+	case NoticeResub:
+		callFuncWithEvent(c.onChatNoticeResub, notice, event, msg)
+	case NoticeSubGift:
+		callFuncWithEvent(c.onChatNoticeSubGift, notice, event, msg)
+	case NoticeCommunitySubGift:
+		callFuncWithEvent(c.onChatNoticeCommunitySubGift, notice, event, msg)
+	case NoticeGiftPaidUpgrade:
+		callFuncWithEvent(c.onChatNoticeGiftPaidUpgrade, notice, event, msg)
+	case NoticePrimePaidUpgrade:
+		callFuncWithEvent(c.onChatNoticePrimePaidUpgrade, notice, event, msg)
+	case NoticePayItForward:
+		callFuncWithEvent(c.onChatNoticePayItForward, notice, event, msg)
+	case NoticeRaid:
+		callFuncWithEvent(c.onChatNoticeRaid, notice, event, msg)
+	case NoticeUnraid:
+		callFuncWithEvent(c.onChatNoticeUnraid, notice, event, msg)
+	case NoticeAnnouncement:
+		callFuncWithEvent(c.onChatNoticeAnnouncement, notice, event, msg)
+	case NoticeBitsBadgeTier:
+		callFuncWithEvent(c.onChatNoticeBitsBadgeTier, notice, event, msg)
+	case NoticeCharityDonation:
+		callFuncWithEvent(c.onChatNoticeCharityDonation, notice, event, msg)
+	}
+}
+
+func (c *Client) handleModerate(event EventChannelModerate, msg NotificationMessage) {
+	switch detail := event.Detail().(type) {
+	case ModerateBan:
+		callFuncWithEvent(c.onModerateBan, detail, event, msg)
+	case ModerateTimeout:
+		callFuncWithEvent(c.onModerateTimeout, detail, event, msg)
+	case ModerateUnban:
+		callFuncWithEvent(c.onModerateUnban, detail, event, msg)
+	case ModerateUntimeout:
+		callFuncWithEvent(c.onModerateUntimeout, detail, event, msg)
+	case ModerateDelete:
+		callFuncWithEvent(c.onModerateDelete, detail, event, msg)
+	case ModerateVip:
+		callFuncWithEvent(c.onModerateVip, detail, event, msg)
+	case ModerateUnvip:
+		callFuncWithEvent(c.onModerateUnvip, detail, event, msg)
+	case ModerateMod:
+		callFuncWithEvent(c.onModerateMod, detail, event, msg)
+	case ModerateUnmod:
+		callFuncWithEvent(c.onModerateUnmod, detail, event, msg)
+	case ModerateRaid:
+		callFuncWithEvent(c.onModerateRaid, detail, event, msg)
+	case ModerateUnraid:
+		callFuncWithEvent(c.onModerateUnraid, detail, event, msg)
+	case ModerateFollowers:
+		callFuncWithEvent(c.onModerateFollowers, detail, event, msg)
+	case ModerateSlow:
+		callFuncWithEvent(c.onModerateSlow, detail, event, msg)
+	case ModerateWarn:
+		callFuncWithEvent(c.onModerateWarn, detail, event, msg)
+	case ModerateAutomodTerms:
+		callFuncWithEvent(c.onModerateAutomodTerms, detail, event, msg)
+	case ModerateUnbanRequest:
+		callFuncWithEvent(c.onModerateUnbanRequest, detail, event, msg)
+	case ModerateChatMode:
+		callFuncWithEvent(c.onModerateChatMode, detail, event, msg)
+	case ModerateClear:
+		callFuncWithEvent(c.onModerateClear, detail, event, msg)
+	}
+}
+
 func (c *Client) handleNotification(message NotificationMessage) error {
 	data, err := message.Payload.Event.MarshalJSON()
 	if err != nil {
@@ -439,6 +659,7 @@ func (c *Client) handleNotification(message NotificationMessage) error {
 		callFuncWithMsg(c.onEventChannelShoutoutReceive, *event, message)
 	case *EventChannelModerate:
 		callFuncWithMsg(c.onEventChannelModerate, *event, message)
+		c.handleModerate(*event, message)
 	case *EventAutomodMessageHold:
 		callFuncWithMsg(c.onEventAutomodMessageHold, *event, message)
 	case *EventAutomodMessageUpdate:
@@ -461,6 +682,7 @@ func (c *Client) handleNotification(message NotificationMessage) error {
 		callFuncWithMsg(c.onEventChannelChatMessageDelete, *event, message)
 	case *EventChannelChatNotification:
 		callFuncWithMsg(c.onEventChannelChatNotification, *event, message)
+		c.handleChatNotice(*event, message)
 	case *EventChannelChatSettingsUpdate:
 		callFuncWithMsg(c.onEventChannelChatSettingsUpdate, *event, message)
 	case *EventChannelSuspiciousUserMessage:
@@ -540,6 +762,26 @@ func (c *Client) OnRevoke(callback func(message RevokeMessage)) {
 	c.onRevoke = callback
 }
 
+func (c *Client) OnRevokeAuthorizationRevoked(callback func(message RevokeMessage)) {
+	c.onRevokeAuthorizationRevoked = callback
+}
+
+func (c *Client) OnRevokeUserRemoved(callback func(message RevokeMessage)) {
+	c.onRevokeUserRemoved = callback
+}
+
+func (c *Client) OnRevokeVersionRemoved(callback func(message RevokeMessage)) {
+	c.onRevokeVersionRemoved = callback
+}
+
+func (c *Client) OnRevokeNotificationFailuresExceeded(callback func(message RevokeMessage)) {
+	c.onRevokeNotificationFailuresExceeded = callback
+}
+
+func (c *Client) OnRevokeModeratorRemoved(callback func(message RevokeMessage)) {
+	c.onRevokeModeratorRemoved = callback
+}
+
 func (c *Client) OnRawEvent(callback func(event string, metadata MessageMetadata, subscription PayloadSubscription)) {
 	c.onRawEvent = callback
 }
@@ -835,3 +1077,123 @@ func (c *Client) OnEventChannelUnbanRequestResolve(callback func(event EventChan
 func (c *Client) OnEventConduitShardDisabled(callback func(event EventConduitShardDisabled, msg NotificationMessage)) {
 	c.onEventConduitShardDisabled = callback
 }
+
+func (c *Client) OnChatNoticeSub(callback func(notice NoticeSub, event EventChannelChatNotification, msg NotificationMessage)) {
+	c.onChatNoticeSub = callback
+}
+
+func (c *Client) OnChatNoticeResub(callback func(notice NoticeResub, event EventChannelChatNotification, msg NotificationMessage)) {
+	c.onChatNoticeResub = callback
+}
+
+func (c *Client) OnChatNoticeSubGift(callback func(notice NoticeSubGift, event EventChannelChatNotification, msg NotificationMessage)) {
+	c.onChatNoticeSubGift = callback
+}
+
+func (c *Client) OnChatNoticeCommunitySubGift(callback func(notice NoticeCommunitySubGift, event EventChannelChatNotification, msg NotificationMessage)) {
+	c.onChatNoticeCommunitySubGift = callback
+}
+
+func (c *Client) OnChatNoticeGiftPaidUpgrade(callback func(notice NoticeGiftPaidUpgrade, event EventChannelChatNotification, msg NotificationMessage)) {
+	c.onChatNoticeGiftPaidUpgrade = callback
+}
+
+func (c *Client) OnChatNoticePrimePaidUpgrade(callback func(notice NoticePrimePaidUpgrade, event EventChannelChatNotification, msg NotificationMessage)) {
+	c.onChatNoticePrimePaidUpgrade = callback
+}
+
+func (c *Client) OnChatNoticePayItForward(callback func(notice NoticePayItForward, event EventChannelChatNotification, msg NotificationMessage)) {
+	c.onChatNoticePayItForward = callback
+}
+
+func (c *Client) OnChatNoticeRaid(callback func(notice NoticeRaid, event EventChannelChatNotification, msg NotificationMessage)) {
+	c.onChatNoticeRaid = callback
+}
+
+func (c *Client) OnChatNoticeUnraid(callback func(notice NoticeUnraid, event EventChannelChatNotification, msg NotificationMessage)) {
+	c.onChatNoticeUnraid = callback
+}
+
+func (c *Client) OnChatNoticeAnnouncement(callback func(notice NoticeAnnouncement, event EventChannelChatNotification, msg NotificationMessage)) {
+	c.onChatNoticeAnnouncement = callback
+}
+
+func (c *Client) OnChatNoticeBitsBadgeTier(callback func(notice NoticeBitsBadgeTier, event EventChannelChatNotification, msg NotificationMessage)) {
+	c.onChatNoticeBitsBadgeTier = callback
+}
+
+func (c *Client) OnChatNoticeCharityDonation(callback func(notice NoticeCharityDonation, event EventChannelChatNotification, msg NotificationMessage)) {
+	c.onChatNoticeCharityDonation = callback
+}
+
+func (c *Client) OnModerateBan(callback func(detail ModerateBan, event EventChannelModerate, msg NotificationMessage)) {
+	c.onModerateBan = callback
+}
+
+func (c *Client) OnModerateTimeout(callback func(detail ModerateTimeout, event EventChannelModerate, msg NotificationMessage)) {
+	c.onModerateTimeout = callback
+}
+
+func (c *Client) OnModerateUnban(callback func(detail ModerateUnban, event EventChannelModerate, msg NotificationMessage)) {
+	c.onModerateUnban = callback
+}
+
+func (c *Client) OnModerateUntimeout(callback func(detail ModerateUntimeout, event EventChannelModerate, msg NotificationMessage)) {
+	c.onModerateUntimeout = callback
+}
+
+func (c *Client) OnModerateDelete(callback func(detail ModerateDelete, event EventChannelModerate, msg NotificationMessage)) {
+	c.onModerateDelete = callback
+}
+
+func (c *Client) OnModerateVip(callback func(detail ModerateVip, event EventChannelModerate, msg NotificationMessage)) {
+	c.onModerateVip = callback
+}
+
+func (c *Client) OnModerateUnvip(callback func(detail ModerateUnvip, event EventChannelModerate, msg NotificationMessage)) {
+	c.onModerateUnvip = callback
+}
+
+func (c *Client) OnModerateMod(callback func(detail ModerateMod, event EventChannelModerate, msg NotificationMessage)) {
+	c.onModerateMod = callback
+}
+
+func (c *Client) OnModerateUnmod(callback func(detail ModerateUnmod, event EventChannelModerate, msg NotificationMessage)) {
+	c.onModerateUnmod = callback
+}
+
+func (c *Client) OnModerateRaid(callback func(detail ModerateRaid, event EventChannelModerate, msg NotificationMessage)) {
+	c.onModerateRaid = callback
+}
+
+func (c *Client) OnModerateUnraid(callback func(detail ModerateUnraid, event EventChannelModerate, msg NotificationMessage)) {
+	c.onModerateUnraid = callback
+}
+
+func (c *Client) OnModerateFollowers(callback func(detail ModerateFollowers, event EventChannelModerate, msg NotificationMessage)) {
+	c.onModerateFollowers = callback
+}
+
+func (c *Client) OnModerateSlow(callback func(detail ModerateSlow, event EventChannelModerate, msg NotificationMessage)) {
+	c.onModerateSlow = callback
+}
+
+func (c *Client) OnModerateWarn(callback func(detail ModerateWarn, event EventChannelModerate, msg NotificationMessage)) {
+	c.onModerateWarn = callback
+}
+
+func (c *Client) OnModerateAutomodTerms(callback func(detail ModerateAutomodTerms, event EventChannelModerate, msg NotificationMessage)) {
+	c.onModerateAutomodTerms = callback
+}
+
+func (c *Client) OnModerateUnbanRequest(callback func(detail ModerateUnbanRequest, event EventChannelModerate, msg NotificationMessage)) {
+	c.onModerateUnbanRequest = callback
+}
+
+func (c *Client) OnModerateChatMode(callback func(detail ModerateChatMode, event EventChannelModerate, msg NotificationMessage)) {
+	c.onModerateChatMode = callback
+}
+
+func (c *Client) OnModerateClear(callback func(detail ModerateClear, event EventChannelModerate, msg NotificationMessage)) {
+	c.onModerateClear = callback
+}
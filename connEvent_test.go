@@ -574,6 +574,18 @@ func TestEventChannelModerate(t *testing.T) {
 	}, twitch.SubChannelModerate)
 }
 
+func TestModerateWarn(t *testing.T) {
+	t.Parallel()
+
+	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
+		client.OnModerateWarn(func(detail twitch.ModerateWarn, event twitch.EventChannelModerate, msg twitch.NotificationMessage) {
+			if detail.UserLogin == "twitchdev" {
+				close(ch)
+			}
+		})
+	}, twitch.SubChannelModerate)
+}
+
 func TestEventAutomodMessageHold(t *testing.T) {
 	t.Parallel()
 
@@ -684,6 +696,18 @@ func TestEventChannelChatNotification(t *testing.T) {
 	}, twitch.SubChannelChatNotification)
 }
 
+func TestChatNoticeResub(t *testing.T) {
+	t.Parallel()
+
+	assertSpecificEventOccurred(t, func(client *twitch.Client, ch chan struct{}) {
+		client.OnChatNoticeResub(func(notice twitch.NoticeResub, event twitch.EventChannelChatNotification, msg twitch.NotificationMessage) {
+			if notice.CumulativeMonths == 10 && !notice.IsSharedChat {
+				close(ch)
+			}
+		})
+	}, twitch.SubChannelChatNotification)
+}
+
 func TestEventChannelChatSettingsUpdate(t *testing.T) {
 	t.Parallel()
 
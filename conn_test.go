@@ -1,7 +1,10 @@
 package twitch_test
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"sync"
 	"testing"
 	"time"
 
@@ -142,6 +145,49 @@ func TestOnRevoke(t *testing.T) {
 	})
 }
 
+func TestOnRevokeAuthorizationRevoked(t *testing.T) {
+	t.Parallel()
+
+	assertEventOccurred(t, func(ch chan struct{}) {
+		client := newClient(t, revokeGen)
+		client.OnRevokeUserRemoved(func(message twitch.RevokeMessage) {
+			t.Error("wrong revocation reason")
+		})
+		client.OnRevokeAuthorizationRevoked(func(message twitch.RevokeMessage) {
+			request := message.Request()
+			assert.Equal(t, twitch.SubChannelFollow, request.Event)
+			assert.Equal(t, "1", request.VersionOverride)
+			assert.Equal(t, "12826", request.Condition["broadcaster_user_id"])
+			close(ch)
+		})
+
+		go connect(t, client)
+	})
+}
+
+func TestOnRevokeModeratorRemoved(t *testing.T) {
+	t.Parallel()
+
+	assertEventOccurred(t, func(ch chan struct{}) {
+		client := newClient(t, func() ([][]byte, bool, error) {
+			data, _, err := revokeGen()
+			if err != nil {
+				return nil, false, err
+			}
+			data[0] = bytes.Replace(data[0], []byte("authorization_revoked"), []byte("moderator_removed"), 1)
+			return data, false, nil
+		})
+		client.OnRevokeAuthorizationRevoked(func(message twitch.RevokeMessage) {
+			t.Error("wrong revocation reason")
+		})
+		client.OnRevokeModeratorRemoved(func(message twitch.RevokeMessage) {
+			close(ch)
+		})
+
+		go connect(t, client)
+	})
+}
+
 func TestOnError(t *testing.T) {
 	t.Parallel()
 
@@ -183,19 +229,57 @@ func TestReconnectEvent(t *testing.T) {
 
 	client := newClient(t, genReconnectGen(reconnectUrl, revokeGen))
 
-	var keepAliveOccurred bool
+	// callbacks run in their own goroutines
+	var mu sync.Mutex
+	var keepAliveOccurred, revokeOccurred bool
+	var welcomes []string
 	client.OnKeepAlive(func(message twitch.KeepAliveMessage) {
+		mu.Lock()
 		keepAliveOccurred = true
+		mu.Unlock()
 		client.Close()
 	})
+	client.OnRevoke(func(message twitch.RevokeMessage) {
+		mu.Lock()
+		defer mu.Unlock()
+		revokeOccurred = true
+	})
+	client.OnWelcome(func(message twitch.WelcomeMessage) {
+		mu.Lock()
+		defer mu.Unlock()
+		welcomes = append(welcomes, message.Payload.Session.ID)
+	})
 
-	var revokeOccurred bool
-	client.OnRevoke(func(message twitch.RevokeMessage) { revokeOccurred = true })
+	var buffer bytes.Buffer
+	client.SetRecorder(twitch.NewRecorder(&buffer))
 
 	err = client.Connect(nil)
 	client.Wait()
 	assert.NoError(t, err)
 	assert.Equal(t, reconnectUrl, client.Address, "addresses should match")
+
+	mu.Lock()
+	defer mu.Unlock()
 	assert.True(t, revokeOccurred, "revoke did not fire")
 	assert.True(t, keepAliveOccurred, "keepalive did not fire")
+	assert.Len(t, welcomes, 2, "reconnect welcome was not handled")
+
+	frames, err := twitch.LoadRecording(&buffer)
+	assert.NoError(t, err)
+	var types []string
+	for _, frame := range frames {
+		var message struct {
+			Metadata twitch.MessageMetadata `json:"metadata"`
+		}
+		json.Unmarshal(frame.Data, &message)
+		types = append(types, message.Metadata.MessageType)
+	}
+	assert.Equal(t, []string{"session_welcome", "session_reconnect", "revocation", "session_welcome", "session_keepalive"}, types)
+
+	if len(frames) == len(types) {
+		var welcome twitch.WelcomeMessage
+		json.Unmarshal(frames[3].Data, &welcome)
+		assert.Contains(t, welcomes, welcome.Payload.Session.ID)
+		assert.Equal(t, welcome.Payload.Session.ID, client.SessionID(), "session was not refreshed by the reconnect welcome")
+	}
 }
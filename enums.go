@@ -0,0 +1,429 @@
+package twitch
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The enum types below keep any value sent by twitch when decoding, IsValid
+// reports whether the value is one of the known constants, which are listed
+// by the function named after the type.
+
+func isEnumValue[T comparable](values []T, value T) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+type SubscriptionTier string
+
+const (
+	SubscriptionTier1 SubscriptionTier = "1000"
+	SubscriptionTier2 SubscriptionTier = "2000"
+	SubscriptionTier3 SubscriptionTier = "3000"
+
+	// SubscriptionTierPrime is never sent by twitch, prime subs are tier 1
+	// with IsPrime set. It's only returned by Plan so prime can be told
+	// apart, and isn't valid or listed by SubscriptionTiers.
+	SubscriptionTierPrime SubscriptionTier = "prime"
+)
+
+var subscriptionTiers = []SubscriptionTier{
+	SubscriptionTier1,
+	SubscriptionTier2,
+	SubscriptionTier3,
+}
+
+func SubscriptionTiers() []SubscriptionTier {
+	return append([]SubscriptionTier(nil), subscriptionTiers...)
+}
+
+func (t SubscriptionTier) IsValid() bool {
+	return isEnumValue(subscriptionTiers, t)
+}
+
+func (t SubscriptionTier) String() string {
+	return string(t)
+}
+
+// Level returns 1, 2 or 3 for the paid tiers, prime counts as 1, and 0 for
+// unknown tiers.
+func (t SubscriptionTier) Level() int {
+	switch t {
+	case SubscriptionTier1, SubscriptionTierPrime:
+		return 1
+	case SubscriptionTier2:
+		return 2
+	case SubscriptionTier3:
+		return 3
+	}
+	return 0
+}
+
+// Display returns a readable name like "Tier 1" or "Prime".
+func (t SubscriptionTier) Display() string {
+	if t == SubscriptionTierPrime {
+		return "Prime"
+	}
+	if t.Level() == 0 {
+		return string(t)
+	}
+	return fmt.Sprintf("Tier %d", t.Level())
+}
+
+func subscriptionPlan(tier SubscriptionTier, isPrime bool) SubscriptionTier {
+	if isPrime {
+		return SubscriptionTierPrime
+	}
+	return tier
+}
+
+// Plan returns the tier of the sub, or SubscriptionTierPrime for prime subs.
+func (n ChatNotificationSub) Plan() SubscriptionTier {
+	return subscriptionPlan(n.SubTier, n.IsPrime)
+}
+
+// Plan returns the tier of the resub, or SubscriptionTierPrime for prime subs.
+func (n ChatNotificationResub) Plan() SubscriptionTier {
+	return subscriptionPlan(n.SubTier, n.IsPrime)
+}
+
+var subscriptionStatuses = []SubscriptionStatus{
+	SubscriptionStatusEnabled,
+	SubscriptionStatusAuthorizationRevoked,
+	SubscriptionStatusModeratorRemoved,
+	SubscriptionStatusUserRemoved,
+	SubscriptionStatusVersionRemoved,
+	SubscriptionStatusNotificationFailuresExceeded,
+	SubscriptionStatusBetaMaintenance,
+	SubscriptionStatusWebsocketDisconnected,
+	SubscriptionStatusWebsocketFailedPingPong,
+	SubscriptionStatusWebsocketReceivedInbound,
+	SubscriptionStatusWebsocketConnectionUnused,
+	SubscriptionStatusWebsocketInternalError,
+	SubscriptionStatusWebsocketNetworkTimeout,
+	SubscriptionStatusWebsocketNetworkError,
+	SubscriptionStatusWebsocketFailedToReconnect,
+}
+
+func SubscriptionStatuses() []SubscriptionStatus {
+	return append([]SubscriptionStatus(nil), subscriptionStatuses...)
+}
+
+func (s SubscriptionStatus) IsValid() bool {
+	return isEnumValue(subscriptionStatuses, s)
+}
+
+func (s SubscriptionStatus) String() string {
+	return string(s)
+}
+
+// SubscriptionStatus returns the status of the subscription as a
+// SubscriptionStatus.
+func (s PayloadSubscription) SubscriptionStatus() SubscriptionStatus {
+	return SubscriptionStatus(s.Status)
+}
+
+type RedemptionStatus string
+
+const (
+	RedemptionStatusUnknown     RedemptionStatus = "unknown"
+	RedemptionStatusUnfulfilled RedemptionStatus = "unfulfilled"
+	RedemptionStatusFulfilled   RedemptionStatus = "fulfilled"
+	RedemptionStatusCanceled    RedemptionStatus = "canceled"
+)
+
+var redemptionStatuses = []RedemptionStatus{
+	RedemptionStatusUnknown,
+	RedemptionStatusUnfulfilled,
+	RedemptionStatusFulfilled,
+	RedemptionStatusCanceled,
+}
+
+func RedemptionStatuses() []RedemptionStatus {
+	return append([]RedemptionStatus(nil), redemptionStatuses...)
+}
+
+func (s RedemptionStatus) IsValid() bool {
+	return isEnumValue(redemptionStatuses, s)
+}
+
+func (s RedemptionStatus) String() string {
+	return string(s)
+}
+
+type PollStatus string
+
+const (
+	PollStatusActive     PollStatus = "active"
+	PollStatusCompleted  PollStatus = "completed"
+	PollStatusTerminated PollStatus = "terminated"
+	PollStatusArchived   PollStatus = "archived"
+	PollStatusModerated  PollStatus = "moderated"
+	PollStatusInvalid    PollStatus = "invalid"
+)
+
+var pollStatuses = []PollStatus{
+	PollStatusActive,
+	PollStatusCompleted,
+	PollStatusTerminated,
+	PollStatusArchived,
+	PollStatusModerated,
+	PollStatusInvalid,
+}
+
+func PollStatuses() []PollStatus {
+	return append([]PollStatus(nil), pollStatuses...)
+}
+
+func (s PollStatus) IsValid() bool {
+	return isEnumValue(pollStatuses, s)
+}
+
+func (s PollStatus) String() string {
+	return string(s)
+}
+
+type PredictionStatus string
+
+const (
+	PredictionStatusActive   PredictionStatus = "active"
+	PredictionStatusLocked   PredictionStatus = "locked"
+	PredictionStatusResolved PredictionStatus = "resolved"
+	PredictionStatusCanceled PredictionStatus = "canceled"
+)
+
+var predictionStatuses = []PredictionStatus{
+	PredictionStatusActive,
+	PredictionStatusLocked,
+	PredictionStatusResolved,
+	PredictionStatusCanceled,
+}
+
+func PredictionStatuses() []PredictionStatus {
+	return append([]PredictionStatus(nil), predictionStatuses...)
+}
+
+func (s PredictionStatus) IsValid() bool {
+	return isEnumValue(predictionStatuses, s)
+}
+
+func (s PredictionStatus) String() string {
+	return string(s)
+}
+
+type GoalType string
+
+const (
+	GoalTypeFollow               GoalType = "follow"
+	GoalTypeSubscription         GoalType = "subscription"
+	GoalTypeSubscriptionCount    GoalType = "subscription_count"
+	GoalTypeNewSubscription      GoalType = "new_subscription"
+	GoalTypeNewSubscriptionCount GoalType = "new_subscription_count"
+	GoalTypeNewBit               GoalType = "new_bit"
+	GoalTypeNewCheerer           GoalType = "new_cheerer"
+)
+
+var goalTypes = []GoalType{
+	GoalTypeFollow,
+	GoalTypeSubscription,
+	GoalTypeSubscriptionCount,
+	GoalTypeNewSubscription,
+	GoalTypeNewSubscriptionCount,
+	GoalTypeNewBit,
+	GoalTypeNewCheerer,
+}
+
+func GoalTypes() []GoalType {
+	return append([]GoalType(nil), goalTypes...)
+}
+
+func (t GoalType) IsValid() bool {
+	return isEnumValue(goalTypes, t)
+}
+
+func (t GoalType) String() string {
+	return string(t)
+}
+
+type HypeTrainContributionType string
+
+const (
+	HypeTrainContributionTypeBits         HypeTrainContributionType = "bits"
+	HypeTrainContributionTypeSubscription HypeTrainContributionType = "subscription"
+	HypeTrainContributionTypeOther        HypeTrainContributionType = "other"
+)
+
+var hypeTrainContributionTypes = []HypeTrainContributionType{
+	HypeTrainContributionTypeBits,
+	HypeTrainContributionTypeSubscription,
+	HypeTrainContributionTypeOther,
+}
+
+func HypeTrainContributionTypes() []HypeTrainContributionType {
+	return append([]HypeTrainContributionType(nil), hypeTrainContributionTypes...)
+}
+
+func (t HypeTrainContributionType) IsValid() bool {
+	return isEnumValue(hypeTrainContributionTypes, t)
+}
+
+func (t HypeTrainContributionType) String() string {
+	return string(t)
+}
+
+type LowTrustStatus string
+
+const (
+	LowTrustStatusNone             LowTrustStatus = "none"
+	LowTrustStatusActiveMonitoring LowTrustStatus = "active_monitoring"
+	LowTrustStatusRestricted       LowTrustStatus = "restricted"
+)
+
+var lowTrustStatuses = []LowTrustStatus{
+	LowTrustStatusNone,
+	LowTrustStatusActiveMonitoring,
+	LowTrustStatusRestricted,
+}
+
+func LowTrustStatuses() []LowTrustStatus {
+	return append([]LowTrustStatus(nil), lowTrustStatuses...)
+}
+
+func (s LowTrustStatus) IsValid() bool {
+	return isEnumValue(lowTrustStatuses, s)
+}
+
+func (s LowTrustStatus) String() string {
+	return string(s)
+}
+
+// AutomodStatus is the status of a message held by automod, or held for
+// chat user message updates.
+type AutomodStatus string
+
+const (
+	AutomodStatusPending  AutomodStatus = "pending"
+	AutomodStatusApproved AutomodStatus = "approved"
+	AutomodStatusDenied   AutomodStatus = "denied"
+	AutomodStatusExpired  AutomodStatus = "expired"
+	AutomodStatusInvalid  AutomodStatus = "invalid"
+)
+
+var automodStatuses = []AutomodStatus{
+	AutomodStatusPending,
+	AutomodStatusApproved,
+	AutomodStatusDenied,
+	AutomodStatusExpired,
+	AutomodStatusInvalid,
+}
+
+func AutomodStatuses() []AutomodStatus {
+	return append([]AutomodStatus(nil), automodStatuses...)
+}
+
+func (s AutomodStatus) IsValid() bool {
+	return isEnumValue(automodStatuses, s)
+}
+
+func (s AutomodStatus) String() string {
+	return string(s)
+}
+
+type UnbanRequestStatus string
+
+const (
+	UnbanRequestStatusApproved UnbanRequestStatus = "approved"
+	UnbanRequestStatusCanceled UnbanRequestStatus = "canceled"
+	UnbanRequestStatusDenied   UnbanRequestStatus = "denied"
+)
+
+var unbanRequestStatuses = []UnbanRequestStatus{
+	UnbanRequestStatusApproved,
+	UnbanRequestStatusCanceled,
+	UnbanRequestStatusDenied,
+}
+
+func UnbanRequestStatuses() []UnbanRequestStatus {
+	return append([]UnbanRequestStatus(nil), unbanRequestStatuses...)
+}
+
+func (s UnbanRequestStatus) IsValid() bool {
+	return isEnumValue(unbanRequestStatuses, s)
+}
+
+func (s UnbanRequestStatus) String() string {
+	return string(s)
+}
+
+type StreamType string
+
+const (
+	StreamTypeLive       StreamType = "live"
+	StreamTypePlaylist   StreamType = "playlist"
+	StreamTypeWatchParty StreamType = "watch_party"
+	StreamTypePremiere   StreamType = "premiere"
+	StreamTypeRerun      StreamType = "rerun"
+)
+
+var streamTypes = []StreamType{
+	StreamTypeLive,
+	StreamTypePlaylist,
+	StreamTypeWatchParty,
+	StreamTypePremiere,
+	StreamTypeRerun,
+}
+
+func StreamTypes() []StreamType {
+	return append([]StreamType(nil), streamTypes...)
+}
+
+func (t StreamType) IsValid() bool {
+	return isEnumValue(streamTypes, t)
+}
+
+func (t StreamType) String() string {
+	return string(t)
+}
+
+// AnnouncementColor is the color of an announcement sent through helix.
+// Primary uses the channel's accent color. Announcement notifications have
+// the same colors in upper case.
+type AnnouncementColor string
+
+const (
+	AnnouncementColorPrimary AnnouncementColor = "primary"
+	AnnouncementColorBlue    AnnouncementColor = "blue"
+	AnnouncementColorGreen   AnnouncementColor = "green"
+	AnnouncementColorOrange  AnnouncementColor = "orange"
+	AnnouncementColorPurple  AnnouncementColor = "purple"
+)
+
+var announcementColors = []AnnouncementColor{
+	AnnouncementColorPrimary,
+	AnnouncementColorBlue,
+	AnnouncementColorGreen,
+	AnnouncementColorOrange,
+	AnnouncementColorPurple,
+}
+
+func AnnouncementColors() []AnnouncementColor {
+	return append([]AnnouncementColor(nil), announcementColors...)
+}
+
+func (c AnnouncementColor) IsValid() bool {
+	return isEnumValue(announcementColors, c)
+}
+
+func (c AnnouncementColor) String() string {
+	return string(c)
+}
+
+// AnnouncementColor returns the color of the announcement, which twitch
+// sends in upper case, as an AnnouncementColor.
+func (n ChatNotificationAnnouncement) AnnouncementColor() AnnouncementColor {
+	return AnnouncementColor(strings.ToLower(n.Color))
+}
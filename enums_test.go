@@ -0,0 +1,84 @@
+package twitch_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/joeyak/go-twitch-eventsub/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubscriptionTierDisplay(t *testing.T) {
+	testCases := []struct {
+		Tier     twitch.SubscriptionTier
+		Expected string
+		Valid    bool
+	}{
+		{twitch.SubscriptionTier1, "Tier 1", true},
+		{twitch.SubscriptionTier2, "Tier 2", true},
+		{twitch.SubscriptionTier3, "Tier 3", true},
+		{twitch.SubscriptionTierPrime, "Prime", false},
+		{"4000", "4000", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Tier.String(), func(t *testing.T) {
+			assert.Equal(t, tc.Expected, tc.Tier.Display())
+			assert.Equal(t, tc.Valid, tc.Tier.IsValid())
+		})
+	}
+}
+
+func TestSubscriptionPlan(t *testing.T) {
+	var sub twitch.ChatNotificationSub
+	err := json.Unmarshal([]byte(`{"sub_tier":"1000","is_prime":true,"duration_months":1}`), &sub)
+	assert.NoError(t, err)
+	assert.Equal(t, twitch.SubscriptionTier1, sub.SubTier)
+	assert.Equal(t, twitch.SubscriptionTierPrime, sub.Plan())
+
+	sub.IsPrime = false
+	assert.Equal(t, "Tier 1", sub.Plan().Display())
+}
+
+func TestEnumsJson(t *testing.T) {
+	var event twitch.EventChannelPollEnd
+	err := json.Unmarshal([]byte(`{"status":"completed"}`), &event)
+	assert.NoError(t, err)
+	assert.Equal(t, twitch.PollStatusCompleted, event.Status)
+	assert.True(t, event.Status.IsValid())
+
+	err = json.Unmarshal([]byte(`{"status":"something_new"}`), &event)
+	assert.NoError(t, err)
+	assert.Equal(t, "something_new", event.Status.String())
+	assert.False(t, event.Status.IsValid())
+
+	data, err := json.Marshal(twitch.EventChannelUnbanRequestResolve{Status: twitch.UnbanRequestStatusDenied})
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"status":"denied"`)
+
+	assert.True(t, twitch.SubscriptionStatusAuthorizationRevoked.IsValid())
+	assert.False(t, twitch.SubscriptionStatus("unknown").IsValid())
+	assert.Equal(t, twitch.SubscriptionStatusUserRemoved, twitch.PayloadSubscription{Status: "user_removed"}.SubscriptionStatus())
+}
+
+func TestEnumValues(t *testing.T) {
+	assert.Equal(t, []twitch.PollStatus{
+		twitch.PollStatusActive, twitch.PollStatusCompleted, twitch.PollStatusTerminated,
+		twitch.PollStatusArchived, twitch.PollStatusModerated, twitch.PollStatusInvalid,
+	}, twitch.PollStatuses())
+	assert.NotContains(t, twitch.SubscriptionTiers(), twitch.SubscriptionTierPrime)
+	assert.Contains(t, twitch.StreamTypes(), twitch.StreamTypeWatchParty)
+
+	statuses := twitch.PollStatuses()
+	statuses[0] = "changed"
+	assert.True(t, twitch.PollStatusActive.IsValid())
+}
+
+func TestAnnouncementColor(t *testing.T) {
+	var announcement twitch.ChatNotificationAnnouncement
+	err := json.Unmarshal([]byte(`{"color":"BLUE"}`), &announcement)
+	assert.NoError(t, err)
+	assert.Equal(t, twitch.AnnouncementColorBlue, announcement.AnnouncementColor())
+	assert.True(t, announcement.AnnouncementColor().IsValid())
+	assert.Contains(t, twitch.AnnouncementColors(), twitch.AnnouncementColorPrimary)
+}
@@ -0,0 +1,252 @@
+package eventgen
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/joeyak/go-twitch-eventsub/v3"
+)
+
+var (
+	emotes       = []string{"Kappa", "PogChamp", "LUL", "SeemsGood", "BibleThump", "HeyGuys", "VoHiYo", "Kreygasm"}
+	cheermotes   = []string{"Cheer", "BibleThump", "cheerwhal", "Corgo", "Kappa"}
+	messageTypes = []string{"text", "text", "text", "text", "channel_points_highlighted", "channel_points_sub_only", "user_intro"}
+
+	// badgeSets leaves out the broadcaster badge, which only the
+	// broadcaster has.
+	badgeSets = []string{
+		twitch.BadgeModerator, twitch.BadgeVIP, twitch.BadgeSubscriber, twitch.BadgeFounder,
+		twitch.BadgeBits, twitch.BadgePartner, twitch.BadgeLeadModerator, twitch.BadgeStaff,
+	}
+)
+
+// chatMessage returns a message of text, mention, emote and cheermote
+// fragments picked with the chances in the options.
+func (g *Generator) chatMessage() twitch.ChatMessage {
+	n := 1
+	if g.MaxFragments > 1 {
+		n += g.rand.Intn(g.MaxFragments)
+	}
+
+	fragments := make([]twitch.ChatMessageFragment, n)
+	for i := range fragments {
+		fragments[i] = g.fragment()
+	}
+	return joinFragments(fragments)
+}
+
+func (g *Generator) fragment() twitch.ChatMessageFragment {
+	chance := g.rand.Float64()
+
+	switch {
+	case chance < g.MentionChance:
+		u := g.user()
+		return twitch.ChatMessageFragment{
+			Type:    "mention",
+			Text:    "@" + u.login,
+			Mention: &twitch.ChatMessageFragmentMention{UserID: u.id, UserLogin: u.login, UserName: u.name},
+		}
+	case chance < g.MentionChance+g.EmoteChance:
+		return twitch.ChatMessageFragment{
+			Type: "emote",
+			Text: emotes[g.rand.Intn(len(emotes))],
+			Emote: &twitch.ChatMessageFragmentEmote{
+				Id:         strconv.Itoa(1 + g.rand.Intn(999999)),
+				EmoteSetId: strconv.Itoa(g.rand.Intn(999999)),
+				OwnerId:    g.user().id,
+				Format:     []string{"static"},
+			},
+		}
+	case chance < g.MentionChance+g.EmoteChance+g.CheermoteChance:
+		prefix := cheermotes[g.rand.Intn(len(cheermotes))]
+		tier := bitsTiers[g.rand.Intn(len(bitsTiers))]
+		bits := tier * (1 + g.rand.Intn(5))
+		return twitch.ChatMessageFragment{
+			Type:      "cheermote",
+			Text:      prefix + strconv.Itoa(bits),
+			Cheermote: &twitch.ChatMessageFragmentCheermote{Prefix: prefix, Bits: bits, Tier: tier},
+		}
+	}
+
+	return twitch.ChatMessageFragment{Type: "text", Text: g.sentence(1 + g.rand.Intn(6))}
+}
+
+// joinFragments returns the message of the fragments, adding the spaces
+// between them that twitch sends as part of the text fragments.
+func joinFragments(fragments []twitch.ChatMessageFragment) twitch.ChatMessage {
+	var message twitch.ChatMessage
+	for _, fragment := range fragments {
+		if n := len(message.Fragments); n > 0 {
+			previous := &message.Fragments[n-1]
+			switch {
+			case previous.Type == "text" && fragment.Type == "text":
+				previous.Text = strings.TrimSuffix(previous.Text, " ") + " " + strings.TrimPrefix(fragment.Text, " ")
+				continue
+			case previous.Type == "text":
+				if !strings.HasSuffix(previous.Text, " ") {
+					previous.Text += " "
+				}
+			case fragment.Type == "text":
+				if !strings.HasPrefix(fragment.Text, " ") {
+					fragment.Text = " " + fragment.Text
+				}
+			default:
+				message.Fragments = append(message.Fragments, twitch.ChatMessageFragment{Type: "text", Text: " "})
+			}
+		}
+		message.Fragments = append(message.Fragments, fragment)
+	}
+
+	var text strings.Builder
+	for _, fragment := range message.Fragments {
+		text.WriteString(fragment.Text)
+	}
+	message.Text = text.String()
+	return message
+}
+
+func (g *Generator) badges() twitch.Badges {
+	badges := twitch.Badges{}
+	for _, i := range g.rand.Perm(len(badgeSets))[:g.rand.Intn(3)] {
+		badge := twitch.ChatMessageUserBadge{SetId: badgeSets[i], Id: "1"}
+		switch badge.SetId {
+		case twitch.BadgeSubscriber, twitch.BadgeFounder:
+			months := 1 + g.rand.Intn(60)
+			badge.Id = strconv.Itoa(months)
+			badge.Info = strconv.Itoa(months)
+		case twitch.BadgeBits:
+			badge.Id = strconv.Itoa(bitsTiers[g.rand.Intn(len(bitsTiers))])
+		}
+		badges = append(badges, badge)
+	}
+	return badges
+}
+
+func (g *Generator) fixChatMessage(event *twitch.EventChannelChatMessage) {
+	g.setSharedChat(reflect.ValueOf(event).Elem(), g.rand.Float64() < g.SharedChatChance)
+
+	event.MessageType = messageTypes[g.rand.Intn(len(messageTypes))]
+	if !strings.HasPrefix(event.MessageType, "channel_points") {
+		event.ChannelPointsCustomRewardId = ""
+	}
+
+	if event.Reply != nil {
+		parent := g.user()
+		event.Reply.ParentUserId = parent.id
+		event.Reply.ParentUserLogin = parent.login
+		event.Reply.ParentUserName = parent.name
+		event.Reply.ThreadMessageId = event.Reply.ParentMessageId
+
+		mention := twitch.ChatMessageFragment{
+			Type:    "mention",
+			Text:    "@" + parent.login,
+			Mention: &twitch.ChatMessageFragmentMention{UserID: parent.id, UserLogin: parent.login, UserName: parent.name},
+		}
+		event.Message = joinFragments(append([]twitch.ChatMessageFragment{mention}, event.Message.Fragments...))
+	}
+
+	event.Cheer = nil
+	for _, fragment := range event.Message.Fragments {
+		if fragment.Cheermote == nil {
+			continue
+		}
+		if event.Cheer == nil {
+			event.Cheer = &twitch.ChatMessageCheer{}
+		}
+		event.Cheer.Bits += fragment.Cheermote.Bits
+	}
+}
+
+func (g *Generator) fixChatNotification(v reflect.Value, event *twitch.EventChannelChatNotification) {
+	g.setSharedChat(v, event.NoticeType.IsSharedChat())
+	g.setOnly(v, string(event.NoticeType))
+
+	// announcement notifications have the helix colors in upper case
+	colors := twitch.AnnouncementColors()
+	for _, announcement := range []*twitch.ChatNotificationAnnouncement{event.Announcement, event.SharedChatAnnouncement} {
+		if announcement != nil {
+			announcement.Color = strings.ToUpper(string(colors[g.rand.Intn(len(colors))]))
+		}
+	}
+
+	if event.ChatterIsAnonymous {
+		event.ChatterUserId = ""
+		event.ChatterUserLogin = "ananonymousgifter"
+		event.ChatterUserName = "AnAnonymousGifter"
+	}
+}
+
+func (g *Generator) fixModerate(v reflect.Value, event *twitch.EventChannelModerate) {
+	g.setSharedChat(v, event.Action.IsSharedChat())
+
+	field, ok := moderateFields[event.Action]
+	if !ok {
+		field = string(event.Action)
+	}
+	g.setOnly(v, field)
+
+	if event.AutomodTerms != nil {
+		parts := strings.SplitN(string(event.Action), "_", 3)
+		event.AutomodTerms.Action = parts[0]
+		event.AutomodTerms.List = parts[1]
+	}
+	if event.UnbanRequest != nil {
+		event.UnbanRequest.IsApproved = event.Action == twitch.ModerateActionApproveUnbanRequest
+	}
+}
+
+// setOnly sets the pointer field named field and clears every other
+// pointer field of the struct, like twitch does for the payloads of
+// notifications and moderate events.
+func (g *Generator) setOnly(v reflect.Value, field string) {
+	for i := 0; i < v.NumField(); i++ {
+		value := v.Field(i)
+		if value.Kind() != reflect.Pointer {
+			continue
+		}
+
+		if jsonName(v.Type().Field(i)) != field {
+			value.Set(reflect.Zero(value.Type()))
+		} else if value.IsNil() {
+			value.Set(reflect.New(value.Type().Elem()))
+			g.fill(value.Elem(), field)
+		}
+	}
+}
+
+// setSharedChat sets the source fields of the event to another channel
+// for shared chat events and clears them otherwise.
+func (g *Generator) setSharedChat(v reflect.Value, shared bool) {
+	source := v.FieldByName("SourceBroadcaster")
+	badges := v.FieldByName("SourceBadges")
+	messageID := v.FieldByName("SourceMessageId")
+
+	if !shared {
+		source.Set(reflect.Zero(source.Type()))
+		if badges.IsValid() {
+			badges.Set(reflect.Zero(badges.Type()))
+		}
+		if messageID.IsValid() {
+			messageID.SetString("")
+		}
+		return
+	}
+
+	broadcasterID := v.FieldByName("BroadcasterUserId").String()
+	u := g.user()
+	for u.id == broadcasterID {
+		u = g.user()
+	}
+	source.Set(reflect.ValueOf(twitch.SourceBroadcaster{
+		SourceBroadcasterUserId:    u.id,
+		SourceBroadcasterUserLogin: u.login,
+		SourceBroadcasterUserName:  u.name,
+	}))
+	if badges.IsValid() {
+		badges.Set(reflect.ValueOf(g.badges()))
+	}
+	if messageID.IsValid() && messageID.String() == "" {
+		messageID.SetString(g.uuid())
+	}
+}
@@ -0,0 +1,214 @@
+// Package eventgen generates random but valid events for every subscription
+// type in twitch.SubMetadata. A generator with the same seed and options
+// always generates the same events, which makes it usable for property and
+// load tests.
+package eventgen
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joeyak/go-twitch-eventsub/v3"
+)
+
+var defaultNow = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+type Options struct {
+	// Now is the time events happen around, defaulting to a fixed date so
+	// output doesn't change between runs.
+	Now time.Time
+	// Users is the number of users that chatters, broadcasters and
+	// moderators are picked from.
+	Users int
+	// PointerChance is the probability of optional pointer fields being
+	// set, from 0 to 1.
+	PointerChance float64
+	// MaxSliceLen is the most elements generated for a slice.
+	MaxSliceLen int
+
+	// MaxFragments is the most fragments of a chat message, and the
+	// chances are the probabilities of a fragment being a mention, emote
+	// or cheermote instead of text.
+	MaxFragments    int
+	MentionChance   float64
+	EmoteChance     float64
+	CheermoteChance float64
+	// SharedChatChance is the probability of chat events coming from
+	// another channel of a shared chat session.
+	SharedChatChance float64
+}
+
+func DefaultOptions() Options {
+	return Options{
+		Now:              defaultNow,
+		Users:            20,
+		PointerChance:    0.5,
+		MaxSliceLen:      3,
+		MaxFragments:     6,
+		MentionChance:    0.1,
+		EmoteChance:      0.2,
+		CheermoteChance:  0.05,
+		SharedChatChance: 0.1,
+	}
+}
+
+type user struct {
+	id    string
+	login string
+	name  string
+}
+
+type Generator struct {
+	Options
+
+	rand  *rand.Rand
+	users []user
+}
+
+func New(seed int64) *Generator {
+	return NewWithOptions(seed, DefaultOptions())
+}
+
+func NewWithOptions(seed int64, opts Options) *Generator {
+	if opts.Now.IsZero() {
+		opts.Now = defaultNow
+	}
+	if opts.Users < 2 {
+		opts.Users = 2
+	}
+
+	g := &Generator{
+		Options: opts,
+		rand:    rand.New(rand.NewSource(seed)),
+	}
+	for i := 0; i < opts.Users; i++ {
+		login := g.word() + strconv.Itoa(g.rand.Intn(1000))
+		g.users = append(g.users, user{
+			id:    strconv.Itoa(10000000 + g.rand.Intn(90000000)),
+			login: login,
+			name:  strings.ToUpper(login[:1]) + login[1:],
+		})
+	}
+	return g
+}
+
+// Subscriptions returns every subscription type events can be generated
+// for, sorted by name.
+func Subscriptions() []twitch.EventSubscription {
+	var subscriptions []twitch.EventSubscription
+	for subscription := range twitch.SubMetadata() {
+		subscriptions = append(subscriptions, subscription)
+	}
+	sort.Slice(subscriptions, func(i, j int) bool { return subscriptions[i] < subscriptions[j] })
+	return subscriptions
+}
+
+// Event returns a random event of the type the subscription is decoded
+// into, like twitch.EventChannelFollow for channel.follow.
+func (g *Generator) Event(subscription twitch.EventSubscription) (any, error) {
+	metadata, ok := twitch.SubMetadata()[subscription]
+	if !ok {
+		return nil, fmt.Errorf("could not generate %s: unknown subscription type", subscription)
+	}
+
+	event := reflect.ValueOf(metadata.EventGen()).Elem()
+	g.fill(event, "")
+	return event.Interface(), nil
+}
+
+// Generate returns a random T.
+func Generate[T any](g *Generator) T {
+	var v T
+	g.fill(reflect.ValueOf(&v).Elem(), "")
+	return v
+}
+
+// Fill sets every field of the value v points to.
+func (g *Generator) Fill(v any) error {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Pointer || value.IsNil() {
+		return fmt.Errorf("could not fill %T: not a pointer", v)
+	}
+
+	g.fill(value.Elem(), "")
+	return nil
+}
+
+// Notification returns a random event wrapped in the notification message
+// twitch sends over the websocket.
+func (g *Generator) Notification(subscription twitch.EventSubscription) (twitch.NotificationMessage, error) {
+	event, err := g.Event(subscription)
+	if err != nil {
+		return twitch.NotificationMessage{}, err
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return twitch.NotificationMessage{}, fmt.Errorf("could not marshal %s event: %w", subscription, err)
+	}
+	raw := json.RawMessage(data)
+
+	var message twitch.NotificationMessage
+	message.Metadata = twitch.MessageMetadata{
+		MessageID:        g.uuid(),
+		MessageType:      "notification",
+		MessageTimestamp: g.Now,
+	}
+	message.Payload.Event = &raw
+	message.Payload.Subscription = twitch.PayloadSubscription{
+		SubscriptionRequest: twitch.SubscriptionRequest{
+			Type:      subscription,
+			Version:   twitch.SubMetadata()[subscription].Version,
+			Condition: condition(event),
+			Transport: twitch.SubscriptionTransport{
+				Method:    "websocket",
+				SessionID: strings.ReplaceAll(g.uuid(), "-", ""),
+			},
+		},
+		ID:       g.uuid(),
+		Status:   string(twitch.SubscriptionStatusEnabled),
+		CreateAt: g.Now.Add(-time.Hour),
+	}
+	return message, nil
+}
+
+// NotificationJSON returns the raw websocket message of a random event.
+func (g *Generator) NotificationJSON(subscription twitch.EventSubscription) ([]byte, error) {
+	message, err := g.Notification(subscription)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(message)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal %s notification: %w", subscription, err)
+	}
+	return data, nil
+}
+
+// condition returns the broadcaster or user of the event as the
+// subscription condition.
+func condition(event any) map[string]string {
+	value := reflect.ValueOf(event)
+	if value.Kind() != reflect.Struct {
+		return map[string]string{}
+	}
+
+	for _, field := range []struct{ Name, Key string }{
+		{"BroadcasterUserId", "broadcaster_user_id"},
+		{"ToBroadcasterUserId", "to_broadcaster_user_id"},
+		{"UserID", "user_id"},
+	} {
+		v := value.FieldByName(field.Name)
+		if v.IsValid() && v.Kind() == reflect.String {
+			return map[string]string{field.Key: v.String()}
+		}
+	}
+	return map[string]string{}
+}
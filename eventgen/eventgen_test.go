@@ -0,0 +1,202 @@
+package eventgen_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joeyak/go-twitch-eventsub/v3"
+	"github.com/joeyak/go-twitch-eventsub/v3/eventgen"
+	"github.com/joeyak/go-twitch-eventsub/v3/eventsubtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotificationJSON(t *testing.T) {
+	g := eventgen.New(1)
+
+	for _, subscription := range eventgen.Subscriptions() {
+		data, err := g.NotificationJSON(subscription)
+		if !assert.NoError(t, err, subscription) {
+			continue
+		}
+
+		var message twitch.NotificationMessage
+		err = json.Unmarshal(data, &message)
+		if !assert.NoError(t, err, subscription) {
+			continue
+		}
+		assert.Equal(t, "notification", message.Metadata.MessageType)
+		assert.Equal(t, subscription, message.Payload.Subscription.Type)
+		assert.Equal(t, twitch.SubMetadata()[subscription].Version, message.Payload.Subscription.Version)
+
+		event := twitch.SubMetadata()[subscription].EventGen()
+		err = json.Unmarshal(*message.Payload.Event, event)
+		assert.NoError(t, err, subscription)
+	}
+}
+
+func TestSeed(t *testing.T) {
+	a, b := eventgen.New(42), eventgen.New(42)
+
+	for _, subscription := range eventgen.Subscriptions() {
+		dataA, err := a.NotificationJSON(subscription)
+		assert.NoError(t, err)
+		dataB, err := b.NotificationJSON(subscription)
+		assert.NoError(t, err)
+		assert.Equal(t, string(dataA), string(dataB), subscription)
+	}
+
+	other, err := eventgen.New(43).NotificationJSON(twitch.SubChannelChatMessage)
+	assert.NoError(t, err)
+	same, err := eventgen.New(42).NotificationJSON(twitch.SubChannelChatMessage)
+	assert.NoError(t, err)
+	assert.NotEqual(t, string(other), string(same))
+}
+
+func TestUnknownSubscription(t *testing.T) {
+	_, err := eventgen.New(1).Event("channel.unknown")
+	assert.Error(t, err)
+
+	err = eventgen.New(1).Fill(twitch.EventChannelFollow{})
+	assert.Error(t, err)
+}
+
+func TestChatMessage(t *testing.T) {
+	opts := eventgen.DefaultOptions()
+	opts.PointerChance = 1
+	opts.CheermoteChance = 0.5
+	opts.SharedChatChance = 0.5
+	g := eventgen.NewWithOptions(7, opts)
+
+	shared := 0
+	for i := 0; i < 200; i++ {
+		event := eventgen.Generate[twitch.EventChannelChatMessage](g)
+
+		var text strings.Builder
+		bits := 0
+		for _, fragment := range event.Message.Fragments {
+			text.WriteString(fragment.Text)
+			if fragment.Cheermote != nil {
+				bits += fragment.Cheermote.Bits
+			}
+		}
+		assert.Equal(t, text.String(), event.Message.Text)
+
+		if bits > 0 && assert.NotNil(t, event.Cheer) {
+			assert.Equal(t, bits, event.Cheer.Bits)
+		} else {
+			assert.Nil(t, event.Cheer)
+		}
+
+		if assert.NotNil(t, event.Reply) {
+			assert.Equal(t, "mention", event.Message.Fragments[0].Type)
+			assert.Equal(t, event.Reply.ParentUserId, event.Message.Fragments[0].Mention.UserID)
+		}
+
+		if event.SourceBroadcasterUserId != "" {
+			shared++
+			assert.NotEqual(t, event.BroadcasterUserId, event.SourceBroadcasterUserId)
+			assert.NotEmpty(t, event.SourceMessageId)
+		} else {
+			assert.Empty(t, event.SourceMessageId)
+			assert.Nil(t, event.SourceBadges)
+		}
+	}
+	assert.Greater(t, shared, 0)
+	assert.Less(t, shared, 200)
+}
+
+func TestPointerChance(t *testing.T) {
+	opts := eventgen.DefaultOptions()
+	opts.PointerChance = 0
+	g := eventgen.NewWithOptions(1, opts)
+
+	event := eventgen.Generate[twitch.EventChannelChatMessage](g)
+	assert.Nil(t, event.Reply)
+
+	opts.PointerChance = 1
+	g = eventgen.NewWithOptions(1, opts)
+
+	event = eventgen.Generate[twitch.EventChannelChatMessage](g)
+	assert.NotNil(t, event.Reply)
+}
+
+func TestNoticeAndDetail(t *testing.T) {
+	g := eventgen.New(3)
+
+	for i := 0; i < 200; i++ {
+		notification := eventgen.Generate[twitch.EventChannelChatNotification](g)
+		assert.NotNil(t, notification.Notice(), notification.NoticeType)
+		assert.Equal(t, notification.NoticeType.IsSharedChat(), notification.SourceBroadcasterUserId != "")
+		if notification.CharityDonation != nil {
+			assert.Equal(t, 2, notification.CharityDonation.Amount.DecimalPlace)
+		}
+		if notification.Announcement != nil {
+			color := notification.Announcement.Color
+			assert.Equal(t, strings.ToUpper(color), color)
+			assert.True(t, notification.Announcement.AnnouncementColor().IsValid(), color)
+		}
+
+		moderate := eventgen.Generate[twitch.EventChannelModerate](g)
+		if assert.NotNil(t, moderate.Detail(), moderate.Action) {
+			assert.Equal(t, moderate.Action, moderate.Detail().ModerateAction())
+		}
+	}
+}
+
+func TestUsers(t *testing.T) {
+	g := eventgen.New(5)
+
+	event := eventgen.Generate[twitch.EventChannelFollow](g)
+	assert.NotEmpty(t, event.UserID)
+	assert.Equal(t, strings.ToLower(event.UserName), event.UserLogin)
+	assert.Equal(t, strings.ToLower(event.BroadcasterUserName), event.BroadcasterUserLogin)
+}
+
+func TestEmit(t *testing.T) {
+	server, err := eventsubtest.NewServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	subscriptions := eventgen.Subscriptions()
+	received := make(chan twitch.EventSubscription, len(subscriptions))
+	client := server.Client()
+	client.OnError(func(err error) { t.Errorf("client registered an error: %v", err) })
+	client.OnWelcome(func(message twitch.WelcomeMessage) {})
+	client.OnRawEvent(func(event string, metadata twitch.MessageMetadata, subscription twitch.PayloadSubscription) {
+		received <- subscription.Type
+	})
+
+	err = client.ConnectWithContext(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	_, err = server.WaitForSession(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := eventgen.New(1)
+	for _, subscription := range subscriptions {
+		event, err := g.Event(subscription)
+		assert.NoError(t, err)
+		assert.NoError(t, server.Emit(subscription, event), subscription)
+	}
+
+	for range subscriptions {
+		select {
+		case <-received:
+		case <-ctx.Done():
+			t.Fatal("not every event was received")
+		}
+	}
+}
@@ -0,0 +1,270 @@
+package eventgen
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joeyak/go-twitch-eventsub/v3"
+)
+
+var (
+	words = []string{
+		"kappa", "pog", "hype", "stream", "chat", "raid", "clip", "emote", "glhf", "gg",
+		"speedrun", "boss", "level", "loot", "victory", "cozy", "coffee", "music", "art", "retro",
+	}
+	languages  = []string{"en", "de", "es", "fr", "ja", "pt"}
+	currencies = []string{"USD", "EUR", "GBP", "CAD"}
+	bitsTiers  = []int{1, 100, 1000, 5000, 10000}
+
+	// enums are the values generated for the enum types. Values twitch
+	// never sends in events, like an active poll in a poll end event, are
+	// left out.
+	enums = map[reflect.Type][]string{
+		reflect.TypeOf(twitch.SubscriptionTier("")):          values(twitch.SubscriptionTiers()),
+		reflect.TypeOf(twitch.RedemptionStatus("")):          values(twitch.RedemptionStatuses(), twitch.RedemptionStatusUnknown),
+		reflect.TypeOf(twitch.PollStatus("")):                values(twitch.PollStatuses(), twitch.PollStatusActive, twitch.PollStatusModerated, twitch.PollStatusInvalid),
+		reflect.TypeOf(twitch.PredictionStatus("")):          values(twitch.PredictionStatuses(), twitch.PredictionStatusActive, twitch.PredictionStatusLocked),
+		reflect.TypeOf(twitch.GoalType("")):                  values(twitch.GoalTypes()),
+		reflect.TypeOf(twitch.HypeTrainContributionType("")): values(twitch.HypeTrainContributionTypes()),
+		reflect.TypeOf(twitch.LowTrustStatus("")):            values(twitch.LowTrustStatuses()),
+		reflect.TypeOf(twitch.AutomodStatus("")):             values(twitch.AutomodStatuses(), twitch.AutomodStatusPending, twitch.AutomodStatusInvalid),
+		reflect.TypeOf(twitch.UnbanRequestStatus("")):        values(twitch.UnbanRequestStatuses()),
+		reflect.TypeOf(twitch.StreamType("")):                values(twitch.StreamTypes()),
+		reflect.TypeOf(twitch.AnnouncementColor("")):         values(twitch.AnnouncementColors()),
+		reflect.TypeOf(twitch.NoticeType("")):                values(twitch.NoticeTypes()),
+		reflect.TypeOf(twitch.ModerateAction("")):            values(twitch.ModerateActions()),
+		reflect.TypeOf(twitch.SubscriptionStatus("")):        {string(twitch.SubscriptionStatusEnabled)},
+	}
+
+	// moderateFields maps the actions whose payload isn't in the field
+	// named after them.
+	moderateFields = map[twitch.ModerateAction]string{
+		twitch.ModerateActionAddBlockedTerm:      "automod_terms",
+		twitch.ModerateActionAddPermittedTerm:    "automod_terms",
+		twitch.ModerateActionRemoveBlockedTerm:   "automod_terms",
+		twitch.ModerateActionRemovePermittedTerm: "automod_terms",
+		twitch.ModerateActionApproveUnbanRequest: "unban_request",
+		twitch.ModerateActionDenyUnbanRequest:    "unban_request",
+		twitch.ModerateActionEmoteOnly:           "",
+		twitch.ModerateActionEmoteOnlyOff:        "",
+		twitch.ModerateActionFollowersOff:        "",
+		twitch.ModerateActionUniqueChat:          "",
+		twitch.ModerateActionUniqueChatOff:       "",
+		twitch.ModerateActionSlowOff:             "",
+		twitch.ModerateActionSubscribers:         "",
+		twitch.ModerateActionSubscribersOff:      "",
+		twitch.ModerateActionClear:               "",
+	}
+
+	timeType    = reflect.TypeOf(time.Time{})
+	messageType = reflect.TypeOf(twitch.ChatMessage{})
+	badgesType  = reflect.TypeOf(twitch.Badges{})
+	moneyType   = reflect.TypeOf(twitch.Money{})
+)
+
+// values returns the enum values as strings without the skipped ones.
+func values[T ~string](all []T, skip ...T) []string {
+	var values []string
+	for _, value := range all {
+		skipped := false
+		for _, s := range skip {
+			skipped = skipped || value == s
+		}
+		if !skipped {
+			values = append(values, string(value))
+		}
+	}
+	return values
+}
+
+// fill sets v to a random value. The json name of the field v is stored in
+// picks what kind of string or number is generated.
+func (g *Generator) fill(v reflect.Value, name string) {
+	switch v.Type() {
+	case timeType:
+		v.Set(reflect.ValueOf(g.Now.Add(-time.Duration(g.rand.Int63n(int64(time.Hour))))))
+		return
+	case messageType:
+		v.Set(reflect.ValueOf(g.chatMessage()))
+		return
+	case badgesType:
+		v.Set(reflect.ValueOf(g.badges()))
+		return
+	case moneyType:
+		v.Set(reflect.ValueOf(g.money()))
+		return
+	}
+
+	if values, ok := enums[v.Type()]; ok {
+		v.SetString(values[g.rand.Intn(len(values))])
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(g.string(name))
+	case reflect.Bool:
+		v.SetBool(g.rand.Intn(2) == 0)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(int64(g.int(name)))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(uint64(g.int(name)))
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(g.rand.Float64() * 100)
+	case reflect.Pointer:
+		if g.rand.Float64() < g.PointerChance {
+			v.Set(reflect.New(v.Type().Elem()))
+			g.fill(v.Elem(), name)
+		} else {
+			v.Set(reflect.Zero(v.Type()))
+		}
+	case reflect.Slice:
+		n := 1
+		if g.MaxSliceLen > 1 {
+			n += g.rand.Intn(g.MaxSliceLen)
+		}
+		slice := reflect.MakeSlice(v.Type(), n, n)
+		for i := 0; i < n; i++ {
+			g.fill(slice.Index(i), name)
+		}
+		v.Set(slice)
+	case reflect.Map:
+		v.Set(reflect.MakeMap(v.Type()))
+	case reflect.Struct:
+		g.fillStruct(v)
+	}
+}
+
+func (g *Generator) fillStruct(v reflect.Value) {
+	var order []string
+	names := map[string]reflect.Value{}
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := jsonName(field)
+		if name == "-" {
+			continue
+		}
+		order = append(order, name)
+		names[name] = v.Field(i)
+		g.fill(v.Field(i), name)
+	}
+
+	// fields like user_id, user_login and user_name describe the same user
+	for _, name := range order {
+		id := names[name]
+		prefix := strings.TrimSuffix(name, "_id")
+		login, ok := names[prefix+"_login"]
+		if prefix == name || !ok || id.Kind() != reflect.String {
+			continue
+		}
+
+		u := g.user()
+		id.SetString(u.id)
+		login.SetString(u.login)
+		if displayName, ok := names[prefix+"_name"]; ok {
+			displayName.SetString(u.name)
+		}
+	}
+
+	switch event := v.Addr().Interface().(type) {
+	case *twitch.EventChannelChatMessage:
+		g.fixChatMessage(event)
+	case *twitch.EventChannelChatNotification:
+		g.fixChatNotification(v, event)
+	case *twitch.EventChannelModerate:
+		g.fixModerate(v, event)
+	}
+}
+
+func jsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" && !field.Anonymous {
+		return field.Name
+	}
+	return name
+}
+
+func (g *Generator) string(name string) string {
+	switch {
+	case name == "color" || name == "background_color":
+		return fmt.Sprintf("#%06X", g.rand.Intn(0x1000000))
+	case name == "language":
+		return languages[g.rand.Intn(len(languages))]
+	case name == "currency":
+		return currencies[g.rand.Intn(len(currencies))]
+	case name == "email":
+		return g.user().login + "@example.com"
+	case name == "viewer_count":
+		return strconv.Itoa(g.int(name))
+	case strings.HasSuffix(name, "_url") || strings.HasPrefix(name, "url") || name == "charity_logo" || name == "charity_website":
+		return "https://example.com/" + g.word() + ".png"
+	case name == "category_id" || name == "owner_id" || name == "emote_set_id":
+		return strconv.Itoa(1 + g.rand.Intn(999999))
+	case name == "user_id" || strings.HasSuffix(name, "_user_id"):
+		return g.user().id
+	case name == "id" || strings.HasSuffix(name, "_id"):
+		return g.uuid()
+	case name == "login" || strings.HasSuffix(name, "_login"):
+		return g.user().login
+	case strings.HasSuffix(name, "_name") && name != "category_name" && name != "charity_name":
+		return g.user().name
+	case name == "type" || name == "method" || name == "status" || name == "action" || name == "prefix" || name == "set_id":
+		return g.word()
+	}
+	return g.sentence(1 + g.rand.Intn(8))
+}
+
+func (g *Generator) int(name string) int {
+	switch {
+	case name == "decimal_places" || name == "decimal_place":
+		return 2
+	case name == "level":
+		return 1 + g.rand.Intn(5)
+	case name == "tier":
+		return bitsTiers[g.rand.Intn(len(bitsTiers))]
+	case strings.HasSuffix(name, "_months") || name == "months" || strings.HasSuffix(name, "_streak"):
+		return 1 + g.rand.Intn(36)
+	case strings.HasSuffix(name, "_seconds") || strings.HasSuffix(name, "_minutes"):
+		return 30 + g.rand.Intn(600)
+	}
+	return 1 + g.rand.Intn(1000)
+}
+
+func (g *Generator) user() user {
+	return g.users[g.rand.Intn(len(g.users))]
+}
+
+func (g *Generator) word() string {
+	return words[g.rand.Intn(len(words))]
+}
+
+func (g *Generator) sentence(n int) string {
+	parts := make([]string, n)
+	for i := range parts {
+		parts[i] = g.word()
+	}
+	return strings.Join(parts, " ")
+}
+
+func (g *Generator) uuid() string {
+	b := make([]byte, 16)
+	g.rand.Read(b)
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+func (g *Generator) money() twitch.Money {
+	return twitch.Money{
+		Value:         100 + g.rand.Intn(100000),
+		DecimalPlaces: 2,
+		Currency:      currencies[g.rand.Intn(len(currencies))],
+	}
+}
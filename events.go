@@ -1,7 +1,6 @@
 package twitch
 
 import (
-	"math"
 	"time"
 )
 
@@ -128,26 +127,26 @@ type EventChannelSubscribe struct {
 	User
 	Broadcaster
 
-	Tier   string `json:"tier"`
-	IsGift bool   `json:"is_gift"`
+	Tier   SubscriptionTier `json:"tier"`
+	IsGift bool             `json:"is_gift"`
 }
 
 type EventChannelSubscriptionEnd struct {
 	User
 	Broadcaster
 
-	Tier   string `json:"tier"`
-	IsGift bool   `json:"is_gift"`
+	Tier   SubscriptionTier `json:"tier"`
+	IsGift bool             `json:"is_gift"`
 }
 
 type EventChannelSubscriptionGift struct {
 	User
 	Broadcaster
 
-	Total           int    `json:"total"`
-	Tier            string `json:"tier"`
-	CumulativeTotal int    `json:"cumulative_total"`
-	IsAnonymous     bool   `json:"is_anonymous"`
+	Total           int              `json:"total"`
+	Tier            SubscriptionTier `json:"tier"`
+	CumulativeTotal int              `json:"cumulative_total"`
+	IsAnonymous     bool             `json:"is_anonymous"`
 }
 
 type Emote struct {
@@ -165,11 +164,11 @@ type EventChannelSubscriptionMessage struct {
 	User
 	Broadcaster
 
-	Tier             string  `json:"tier"`
-	Message          Message `json:"message"`
-	CumulativeMonths int     `json:"cumulative_months"`
-	StreakMonths     int     `json:"streak_months"`
-	DurationMonths   int     `json:"duration_months"`
+	Tier             SubscriptionTier `json:"tier"`
+	Message          Message          `json:"message"`
+	CumulativeMonths int              `json:"cumulative_months"`
+	StreakMonths     int              `json:"streak_months"`
+	DurationMonths   int              `json:"duration_months"`
 }
 
 type EventChannelCheer struct {
@@ -181,6 +180,10 @@ type EventChannelCheer struct {
 	IsAnonymous bool   `json:"is_anonymous"`
 }
 
+func (e EventChannelCheer) Amount() Money {
+	return Bits(e.Bits)
+}
+
 type EventChannelRaid struct {
 	FromBroadcaster
 	ToBroadcaster
@@ -280,7 +283,7 @@ type EventChannelChannelPointsCustomRewardRedemptionAdd struct {
 
 	ID         string                   `json:"id"`
 	UserInput  string                   `json:"user_input"`
-	Status     string                   `json:"status"`
+	Status     RedemptionStatus         `json:"status"`
 	Reward     CustomChannelPointReward `json:"reward"`
 	RedeemedAt time.Time                `json:"redeemed_at"`
 }
@@ -339,7 +342,7 @@ type EventChannelPollProgress EventChannelPollBegin
 type EventChannelPollEnd struct {
 	EventChannelPollBegin
 
-	Status string `json:"status"`
+	Status PollStatus `json:"status"`
 }
 
 type TopPredictor struct {
@@ -379,7 +382,7 @@ type EventChannelPredictionEnd struct {
 	Title            string              `json:"title"`
 	WinningOutcomeID string              `json:"winning_outcome_id"`
 	Outcomes         []PredictionOutcome `json:"outcomes"`
-	Status           string              `json:"status"`
+	Status           PredictionStatus    `json:"status"`
 	StartedAt        time.Time           `json:"started_at"`
 	EndedAt          time.Time           `json:"ended_at"`
 }
@@ -408,6 +411,10 @@ type ExtensionProduct struct {
 	InDevelopment bool   `json:"in_development"`
 }
 
+func (p ExtensionProduct) Amount() Money {
+	return Bits(p.Bits)
+}
+
 type EventExtensionBitsTransactionCreate struct {
 	Broadcaster
 	User
@@ -421,7 +428,7 @@ type EventChannelGoalBegin struct {
 	Broadcaster
 
 	ID            string    `json:"id"`
-	Type          string    `json:"type"`
+	Type          GoalType  `json:"type"`
 	Description   string    `json:"description"`
 	CurrentAmount int       `json:"current_amount"`
 	TargetAmount  int       `json:"target_amount"`
@@ -440,8 +447,8 @@ type EventChannelGoalEnd struct {
 type HypeTrainContribution struct {
 	User
 
-	Type  string `json:"type"`
-	Total int    `json:"total"`
+	Type  HypeTrainContributionType `json:"type"`
+	Total int                       `json:"total"`
 }
 
 type EventChannelHypeTrainBegin struct {
@@ -473,15 +480,16 @@ type EventChannelHypeTrainEnd struct {
 	TopContributions []HypeTrainContribution `json:"top_contributions"`
 	StartedAt        time.Time               `json:"started_at"`
 	ExpiresAt        time.Time               `json:"expires_at"`
+	EndedAt          time.Time               `json:"ended_at"`
 	CooldownEndsAt   time.Time               `json:"cooldown_ends_at"`
 }
 
 type EventStreamOnline struct {
 	Broadcaster
 
-	Id        string    `json:"id"`
-	Type      string    `json:"type"`
-	StartedAt time.Time `json:"started_at"`
+	Id        string     `json:"id"`
+	Type      StreamType `json:"type"`
+	StartedAt time.Time  `json:"started_at"`
 }
 
 type EventStreamOffline Broadcaster
@@ -502,15 +510,7 @@ type EventUserUpdate struct {
 	Description   string `json:"description"`
 }
 
-type GoalAmount struct {
-	Value         int    `json:"value"`
-	DecimalPlaces int    `json:"decimal_places"`
-	Currency      string `json:"currency"`
-}
-
-func (a GoalAmount) Amount() float64 {
-	return float64(a.Value) / math.Pow10(a.DecimalPlaces)
-}
+type GoalAmount = Money
 
 type BaseCharity struct {
 	Broadcaster
@@ -526,14 +526,40 @@ type BaseCharity struct {
 type EventChannelCharityCampaignDonate struct {
 	BaseCharity
 
-	Amount GoalAmount `json:"amount"`
+	ID     string `json:"id"`
+	Amount Money  `json:"amount"`
 }
 
 type EventChannelCharityCampaignProgress struct {
 	BaseCharity
 
-	CurrentAmount GoalAmount `json:"current_amount"`
-	TargetAmount  GoalAmount `json:"target_amount"`
+	// Start, progress and stop name the campaign and broadcaster differently
+	// than donate, use Campaign to read them from either.
+	ID               string `json:"id"`
+	BroadcasterId    string `json:"broadcaster_id"`
+	BroadcasterLogin string `json:"broadcaster_login"`
+	BroadcasterName  string `json:"broadcaster_name"`
+
+	CurrentAmount Money `json:"current_amount"`
+	TargetAmount  Money `json:"target_amount"`
+}
+
+// Campaign returns the campaign ID and the broadcaster of the campaign.
+func (e EventChannelCharityCampaignProgress) Campaign() (string, Broadcaster) {
+	campaignID := e.CampaignID
+	if campaignID == "" {
+		campaignID = e.ID
+	}
+
+	broadcaster := e.Broadcaster
+	if broadcaster.BroadcasterUserId == "" {
+		broadcaster = Broadcaster{
+			BroadcasterUserId:    e.BroadcasterId,
+			BroadcasterUserLogin: e.BroadcasterLogin,
+			BroadcasterUserName:  e.BroadcasterName,
+		}
+	}
+	return campaignID, broadcaster
 }
 
 type EventChannelCharityCampaignStart struct {
@@ -620,9 +646,9 @@ type EventChannelUnbanRequestResolve struct {
 	Moderator
 	User
 
-	Id             string `json:"id"`
-	ResolutionText string `json:"resolution_text"`
-	Status         string `json:"status"`
+	Id             string             `json:"id"`
+	ResolutionText string             `json:"resolution_text"`
+	Status         UnbanRequestStatus `json:"status"`
 }
 
 type EventChannelSharedChatBegin struct {
@@ -662,7 +688,7 @@ type EventChannelModerate struct {
 	SourceBroadcaster
 	Moderator
 
-	Action              string          `json:"action"`
+	Action              ModerateAction  `json:"action"`
 	Followers           *Followers      `json:"followers,omitempty"`
 	Slow                *SlowMode       `json:"slow,omitempty"`
 	Vip                 *User           `json:"vip,omitempty"`
@@ -682,7 +708,7 @@ type EventChannelModerate struct {
 	SharedChatBan       *Ban            `json:"shared_chat_ban,omitempty"`
 	SharedChatUnban     *User           `json:"shared_chat_unban,omitempty"`
 	SharedChatTimeout   *Timeout        `json:"shared_chat_timeout,omitempty"`
-	SharedChatuntimeout *User           `json:"shared_chat_untimeout,omitempty"`
+	SharedChatUntimeout *User           `json:"shared_chat_untimeout,omitempty"`
 	SharedChatDelete    *DeletedMessage `json:"shared_chat_delete,omitempty"`
 }
 
@@ -730,12 +756,12 @@ type EventAutomodMessageUpdate struct {
 	User
 	Moderator
 
-	MessageId string      `json:"message_id"`
-	Message   ChatMessage `json:"message"`
-	Level     int         `json:"level"`
-	Category  string      `json:"category"`
-	Status    string      `json:"status"`
-	HeldAt    time.Time   `json:"held_at"`
+	MessageId string        `json:"message_id"`
+	Message   ChatMessage   `json:"message"`
+	Level     int           `json:"level"`
+	Category  string        `json:"category"`
+	Status    AutomodStatus `json:"status"`
+	HeldAt    time.Time     `json:"held_at"`
 }
 
 type EventAutomodSettingsUpdate struct {
@@ -774,9 +800,9 @@ type EventChannelChatUserMessageUpdate struct {
 	Broadcaster
 	User
 
-	Status    string      `json:"status"`
-	MessageId string      `json:"message_id"`
-	Message   ChatMessage `json:"message"`
+	Status    AutomodStatus `json:"status"`
+	MessageId string        `json:"message_id"`
+	Message   ChatMessage   `json:"message"`
 }
 
 type EventChannelChatClear Broadcaster
@@ -813,16 +839,35 @@ type EventChannelChatMessage struct {
 	SourceBroadcaster
 	Chatter
 
-	MessageId                   string                 `json:"message_id"`
-	SourceMessageId             string                 `json:"source_message_id"`
-	Message                     ChatMessage            `json:"message"`
-	Color                       string                 `json:"color"`
-	Badges                      []ChatMessageUserBadge `json:"badges"`
-	SourceBadges                []ChatMessageUserBadge `json:"source_badges"`
-	MessageType                 string                 `json:"message_type"`
-	Cheer                       *ChatMessageCheer      `json:"cheer,omitempty"`
-	Reply                       *ChatMessageReply      `json:"reply,omitempty"`
-	ChannelPointsCustomRewardId string                 `json:"channel_points_custom_reward_id"`
+	MessageId                   string            `json:"message_id"`
+	SourceMessageId             string            `json:"source_message_id"`
+	Message                     ChatMessage       `json:"message"`
+	Color                       string            `json:"color"`
+	Badges                      Badges            `json:"badges"`
+	SourceBadges                Badges            `json:"source_badges"`
+	MessageType                 string            `json:"message_type"`
+	Cheer                       *ChatMessageCheer `json:"cheer,omitempty"`
+	Reply                       *ChatMessageReply `json:"reply,omitempty"`
+	ChannelPointsCustomRewardId string            `json:"channel_points_custom_reward_id"`
+}
+
+// PermissionLevel is the chatter's level in the broadcaster's channel. For
+// shared chat messages that isn't their level in the channel the message
+// came from, which is in SourceBadges.
+func (e EventChannelChatMessage) PermissionLevel() PermissionLevel {
+	if e.ChatterUserId != "" && e.ChatterUserId == e.BroadcasterUserId {
+		return PermissionBroadcaster
+	}
+	return e.Badges.PermissionLevel()
+}
+
+// OriginBadges returns the chatter's badges in the channel the message was
+// sent in.
+func (e EventChannelChatMessage) OriginBadges() Badges {
+	if e.SourceBadges != nil {
+		return e.SourceBadges
+	}
+	return e.Badges
 }
 
 type EventChannelChatMessageDelete struct {
@@ -833,39 +878,39 @@ type EventChannelChatMessageDelete struct {
 }
 
 type ChatNotificationSub struct {
-	SubTier        string `json:"sub_tier"`
-	IsPrime        bool   `json:"is_prime"`
-	DurationMonths int    `json:"duration_months"`
+	SubTier        SubscriptionTier `json:"sub_tier"`
+	IsPrime        bool             `json:"is_prime"`
+	DurationMonths int              `json:"duration_months"`
 }
 
 type ChatNotificationResub struct {
-	CumulativeMonths  int    `json:"cumulative_months"`
-	DurationMonths    int    `json:"duration_months"`
-	StreakMonths      int    `json:"streak_months"`
-	SubTier           string `json:"sub_tier"`
-	IsPrime           bool   `json:"is_prime"`
-	IsGift            bool   `json:"is_gift"`
-	GifterIsAnonymous bool   `json:"gifter_is_anonymous"`
-	GifterUserId      string `json:"gifter_user_id"`
-	GifterUserName    string `json:"gifter_user_name"`
-	GifterUserLogin   string `json:"gifter_user_login"`
+	CumulativeMonths  int              `json:"cumulative_months"`
+	DurationMonths    int              `json:"duration_months"`
+	StreakMonths      int              `json:"streak_months"`
+	SubTier           SubscriptionTier `json:"sub_tier"`
+	IsPrime           bool             `json:"is_prime"`
+	IsGift            bool             `json:"is_gift"`
+	GifterIsAnonymous bool             `json:"gifter_is_anonymous"`
+	GifterUserId      string           `json:"gifter_user_id"`
+	GifterUserName    string           `json:"gifter_user_name"`
+	GifterUserLogin   string           `json:"gifter_user_login"`
 }
 
 type ChatNotificationSubGift struct {
-	DurationMonths     int    `json:"duration_months"`
-	CumulativeTotal    int    `json:"cumulative_total"`
-	RecipientUserId    string `json:"recipient_user_id"`
-	RecipientUserName  string `json:"recipient_user_name"`
-	RecipientUserLogin string `json:"recipient_user_login"`
-	SubTier            string `json:"sub_tier"`
-	CommunityGiftId    string `json:"community_gift_id"`
+	DurationMonths     int              `json:"duration_months"`
+	CumulativeTotal    int              `json:"cumulative_total"`
+	RecipientUserId    string           `json:"recipient_user_id"`
+	RecipientUserName  string           `json:"recipient_user_name"`
+	RecipientUserLogin string           `json:"recipient_user_login"`
+	SubTier            SubscriptionTier `json:"sub_tier"`
+	CommunityGiftId    string           `json:"community_gift_id"`
 }
 
 type ChatNotificationCommunitySubGift struct {
-	Id              string `json:"id"`
-	Total           int    `json:"total"`
-	SubTier         string `json:"sub_tier"`
-	CumulativeTotal int    `json:"cumulative_total"`
+	Id              string           `json:"id"`
+	Total           int              `json:"total"`
+	SubTier         SubscriptionTier `json:"sub_tier"`
+	CumulativeTotal int              `json:"cumulative_total"`
 }
 
 type ChatNotificationGiftPaidUpgrade struct {
@@ -875,7 +920,7 @@ type ChatNotificationGiftPaidUpgrade struct {
 }
 
 type ChatNotificationPrimePaidUpgrade struct {
-	SubTier string `json:"sub_tier"`
+	SubTier SubscriptionTier `json:"sub_tier"`
 }
 
 type ChatNotificationPayItForward struct {
@@ -908,6 +953,11 @@ type ChatNotificationCharityDonationAmount struct {
 	Currency     string `json:"currency"`
 }
 
+// Money converts the amount to Money.
+func (a ChatNotificationCharityDonationAmount) Money() Money {
+	return Money{Value: a.Value, DecimalPlaces: a.DecimalPlace, Currency: a.Currency}
+}
+
 type ChatNotificationCharityDonation struct {
 	CharityName string                                `json:"charity_name"`
 	Amount      ChatNotificationCharityDonationAmount `json:"amount"`
@@ -918,16 +968,16 @@ type EventChannelChatNotification struct {
 	SourceBroadcaster
 	Chatter
 
-	ChatterIsAnonymous bool                   `json:"chatter_is_anonymous"`
-	Color              string                 `json:"color"`
-	Badges             []ChatMessageUserBadge `json:"badges"`
-	SourceBadges       []ChatMessageUserBadge `json:"source_badges"`
-	SystemMessage      string                 `json:"system_message"`
-	MessageId          string                 `json:"message_id"`
-	SourceMessageId    string                 `json:"source_message_id"`
-	Message            ChatMessage            `json:"message"`
+	ChatterIsAnonymous bool        `json:"chatter_is_anonymous"`
+	Color              string      `json:"color"`
+	Badges             Badges      `json:"badges"`
+	SourceBadges       Badges      `json:"source_badges"`
+	SystemMessage      string      `json:"system_message"`
+	MessageId          string      `json:"message_id"`
+	SourceMessageId    string      `json:"source_message_id"`
+	Message            ChatMessage `json:"message"`
 
-	NoticeType       string                            `json:"notice_type"`
+	NoticeType       NoticeType                        `json:"notice_type"`
 	Sub              *ChatNotificationSub              `json:"sub,omitempty"`
 	Resub            *ChatNotificationResub            `json:"resub,omitempty"`
 	SubGift          *ChatNotificationSubGift          `json:"sub_gift,omitempty"`
@@ -952,6 +1002,20 @@ type EventChannelChatNotification struct {
 	SharedChatAnnouncement     *ChatNotificationAnnouncement     `json:"shared_chat_announcement,omitempty"`
 }
 
+func (e EventChannelChatNotification) PermissionLevel() PermissionLevel {
+	if e.ChatterUserId != "" && e.ChatterUserId == e.BroadcasterUserId {
+		return PermissionBroadcaster
+	}
+	return e.Badges.PermissionLevel()
+}
+
+func (e EventChannelChatNotification) OriginBadges() Badges {
+	if e.SourceBadges != nil {
+		return e.SourceBadges
+	}
+	return e.Badges
+}
+
 type EventChannelChatSettingsUpdate struct {
 	Broadcaster
 
@@ -974,7 +1038,7 @@ type EventChannelSuspiciousUserMessage struct {
 	Broadcaster
 	User
 
-	LowTrustStatus       string                    `json:"low_trust_status"`
+	LowTrustStatus       LowTrustStatus            `json:"low_trust_status"`
 	SharedBanChannelIds  []string                  `json:"shared_ban_channel_ids"`
 	Types                []string                  `json:"types"`
 	BanEvasionEvaluation string                    `json:"ban_evasion_evaluation"`
@@ -986,7 +1050,7 @@ type EventChannelSuspiciousUserUpdate struct {
 	User
 	Moderator
 
-	LowTrustStatus string `json:"low_trust_status"`
+	LowTrustStatus LowTrustStatus `json:"low_trust_status"`
 }
 
 type ConduitTransport struct {
@@ -0,0 +1,169 @@
+package eventsubtest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/joeyak/go-twitch-eventsub/v3"
+)
+
+// TwitchCLIAddress is where the twitch CLI's websocket server listens.
+// Starting a Server on it lets twitch.NewCLIClient connect without the CLI.
+const TwitchCLIAddress = "127.0.0.1:8080"
+
+// Fixture is an event in the format printed by `twitch event trigger`.
+type Fixture struct {
+	Subscription twitch.PayloadSubscription `json:"subscription"`
+	Event        json.RawMessage            `json:"event"`
+}
+
+type fixtureJson struct {
+	Subscription *twitch.PayloadSubscription `json:"subscription"`
+	Event        json.RawMessage             `json:"event"`
+	// Events is used by batched events like drop entitlements.
+	Events json.RawMessage `json:"events"`
+	// Payload is set for websocket notifications.
+	Payload *fixtureJson `json:"payload"`
+}
+
+// LoadFixtures reads the fixtures printed by `twitch event trigger`. Both
+// the webhook output and the websocket notification of `--transport
+// websocket` are accepted, as a single value, an array or one value after
+// the other.
+func LoadFixtures(r io.Reader) ([]Fixture, error) {
+	decoder := json.NewDecoder(r)
+
+	var fixtures []Fixture
+	for {
+		var value json.RawMessage
+		err := decoder.Decode(&value)
+		if errors.Is(err, io.EOF) {
+			return fixtures, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not decode fixture: %w", err)
+		}
+
+		var values []json.RawMessage
+		if bytes.HasPrefix(bytes.TrimSpace(value), []byte("[")) {
+			err = json.Unmarshal(value, &values)
+			if err != nil {
+				return nil, fmt.Errorf("could not decode fixtures: %w", err)
+			}
+		} else {
+			values = []json.RawMessage{value}
+		}
+
+		for _, value := range values {
+			fixture, err := parseFixture(value)
+			if err != nil {
+				return nil, err
+			}
+			fixtures = append(fixtures, fixture)
+		}
+	}
+}
+
+func LoadFixtureFile(path string) ([]Fixture, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not open fixture file: %w", err)
+	}
+	defer file.Close()
+
+	return LoadFixtures(file)
+}
+
+func parseFixture(data []byte) (Fixture, error) {
+	var value fixtureJson
+	err := json.Unmarshal(data, &value)
+	if err != nil {
+		return Fixture{}, fmt.Errorf("could not decode fixture: %w", err)
+	}
+
+	if value.Payload != nil {
+		value = *value.Payload
+	}
+	if value.Subscription == nil || value.Subscription.Type == "" {
+		return Fixture{}, fmt.Errorf("could not decode fixture: missing subscription type")
+	}
+
+	fixture := Fixture{Subscription: *value.Subscription, Event: value.Event}
+	if len(fixture.Event) == 0 {
+		fixture.Event = value.Events
+	}
+	return fixture, nil
+}
+
+// EmitFixture sends the fixture like Emit. The subscription of the fixture
+// is used when no session is subscribed to its type.
+func (s *Server) EmitFixture(fixture Fixture) error {
+	subscription := fixture.Subscription
+	if subscription.Status == "" {
+		subscription.Status = string(twitch.SubscriptionStatusEnabled)
+	}
+	return s.emit(subscription, fixture.Event)
+}
+
+// Replay emits the fixtures in order, waiting interval between them.
+func (s *Server) Replay(ctx context.Context, fixtures []Fixture, interval time.Duration) error {
+	for i, fixture := range fixtures {
+		if i > 0 && interval > 0 {
+			timer := time.NewTimer(interval)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			case <-timer.C:
+			}
+		}
+
+		err := s.EmitFixture(fixture)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Replayer replays a recording of a live session to every connected
+// session. Notifications and revocations are sent as they were recorded,
+// the other messages are left to the server.
+func (s *Server) Replayer(frames []twitch.RecordedFrame) *twitch.Replayer {
+	return twitch.NewReplayerWithHandler(frames, s.forward)
+}
+
+func (s *Server) forward(data []byte) error {
+	var message struct {
+		Metadata twitch.MessageMetadata `json:"metadata"`
+	}
+	err := json.Unmarshal(data, &message)
+	if err != nil {
+		return fmt.Errorf("could not decode frame: %w", err)
+	}
+
+	switch message.Metadata.MessageType {
+	case "notification", "revocation":
+	default:
+		return nil
+	}
+
+	sessions := s.connected()
+	if len(sessions) == 0 {
+		return fmt.Errorf("could not forward %s: %w", message.Metadata.MessageType, ErrNoSession)
+	}
+
+	for _, session := range sessions {
+		err := s.writeRaw(session, data)
+		if err != nil {
+			return fmt.Errorf("could not forward %s: %w", message.Metadata.MessageType, err)
+		}
+	}
+	return nil
+}
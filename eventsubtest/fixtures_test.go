@@ -0,0 +1,202 @@
+package eventsubtest_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joeyak/go-twitch-eventsub/v3"
+	"github.com/joeyak/go-twitch-eventsub/v3/eventsubtest"
+	"github.com/stretchr/testify/assert"
+)
+
+// Output of `twitch event trigger channel.follow` followed by
+// `twitch event trigger channel.raid --transport=websocket`
+const cliFixtures = `{
+	"subscription": {
+		"id": "f1c2a387-161a-49f9-a165-0f21d7a4e1c4",
+		"status": "enabled",
+		"type": "channel.follow",
+		"version": "2",
+		"condition": {"broadcaster_user_id": "1337", "moderator_user_id": "1337"},
+		"transport": {"method": "webhook", "callback": "null"},
+		"created_at": "2024-01-01T12:00:00.000000000Z",
+		"cost": 0
+	},
+	"event": {
+		"user_id": "25578051",
+		"user_login": "testFromUser",
+		"user_name": "testFromUser",
+		"broadcaster_user_id": "1337",
+		"broadcaster_user_login": "testBroadcaster",
+		"broadcaster_user_name": "testBroadcaster",
+		"followed_at": "2024-01-01T12:00:00.000000000Z"
+	}
+}
+{
+	"metadata": {
+		"message_id": "befa7b53-d79d-478f-86b9-120f112b044e",
+		"message_type": "notification",
+		"message_timestamp": "2024-01-01T12:00:01.000000000Z",
+		"subscription_type": "channel.raid",
+		"subscription_version": "1"
+	},
+	"payload": {
+		"subscription": {
+			"id": "f1c2a387-161a-49f9-a165-0f21d7a4e1c5",
+			"status": "enabled",
+			"type": "channel.raid",
+			"version": "1",
+			"condition": {"to_broadcaster_user_id": "1337"},
+			"transport": {"method": "websocket", "session_id": "session"},
+			"created_at": "2024-01-01T12:00:00.000000000Z",
+			"cost": 0
+		},
+		"event": {
+			"from_broadcaster_user_id": "42",
+			"from_broadcaster_user_login": "testFromUser",
+			"from_broadcaster_user_name": "testFromUser",
+			"to_broadcaster_user_id": "1337",
+			"to_broadcaster_user_login": "testBroadcaster",
+			"to_broadcaster_user_name": "testBroadcaster",
+			"viewers": 9001
+		}
+	}
+}`
+
+func TestLoadFixtures(t *testing.T) {
+	fixtures, err := eventsubtest.LoadFixtures(strings.NewReader(cliFixtures))
+	assert.NoError(t, err)
+	if assert.Len(t, fixtures, 2) {
+		assert.Equal(t, twitch.SubChannelFollow, fixtures[0].Subscription.Type)
+		assert.Equal(t, "1337", fixtures[0].Subscription.Condition["broadcaster_user_id"])
+		assert.Equal(t, twitch.SubChannelRaid, fixtures[1].Subscription.Type)
+		assert.Contains(t, string(fixtures[1].Event), "9001")
+	}
+
+	array, err := eventsubtest.LoadFixtures(strings.NewReader("[" + strings.Replace(cliFixtures, "}\n{", "},\n{", 1) + "]"))
+	assert.NoError(t, err)
+	assert.Equal(t, fixtures, array)
+
+	_, err = eventsubtest.LoadFixtures(strings.NewReader(`{"event":{}}`))
+	assert.Error(t, err)
+}
+
+func TestServerReplay(t *testing.T) {
+	server := newServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	fixtures, err := eventsubtest.LoadFixtures(strings.NewReader(cliFixtures))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	follows := make(chan twitch.EventChannelFollow, 1)
+	raids := make(chan twitch.NotificationMessage, 1)
+	client := server.Client()
+	client.OnWelcome(func(message twitch.WelcomeMessage) {})
+	client.OnEventChannelFollow(func(event twitch.EventChannelFollow, msg twitch.NotificationMessage) { follows <- event })
+	client.OnEventChannelRaid(func(event twitch.EventChannelRaid, msg twitch.NotificationMessage) { raids <- msg })
+
+	err = client.ConnectWithContext(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	sessionID, err := server.WaitForSession(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = server.Replay(ctx, fixtures, 10*time.Millisecond)
+	assert.NoError(t, err)
+
+	select {
+	case event := <-follows:
+		assert.Equal(t, "testFromUser", event.UserLogin)
+	case <-ctx.Done():
+		t.Fatal("follow was not received")
+	}
+
+	select {
+	case msg := <-raids:
+		assert.Equal(t, sessionID, msg.Payload.Subscription.Transport.SessionID)
+		assert.Equal(t, "1337", msg.Payload.Subscription.Condition["to_broadcaster_user_id"])
+	case <-ctx.Done():
+		t.Fatal("raid was not received")
+	}
+}
+
+func TestServerReplayer(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// record a follow sent by one server
+	var recording bytes.Buffer
+	recorded := make(chan struct{})
+	server := newServer(t)
+	client := server.Client()
+	client.SetRecorder(twitch.NewRecorder(&recording))
+	client.OnWelcome(func(message twitch.WelcomeMessage) {})
+	client.OnEventChannelFollow(func(event twitch.EventChannelFollow, msg twitch.NotificationMessage) { close(recorded) })
+
+	err := client.ConnectWithContext(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	_, err = server.WaitForSession(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = server.Emit(twitch.SubChannelFollow, twitch.EventChannelFollow{User: twitch.User{UserLogin: "follower"}})
+	assert.NoError(t, err)
+
+	select {
+	case <-recorded:
+	case <-ctx.Done():
+		t.Fatal("follow was not recorded")
+	}
+
+	frames, err := twitch.LoadRecording(&recording)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// and replay it through another
+	follows := make(chan twitch.EventChannelFollow, 1)
+	replayServer := newServer(t)
+	replayClient := replayServer.Client()
+	replayClient.OnError(func(err error) { t.Errorf("client registered an error: %v", err) })
+	replayClient.OnWelcome(func(message twitch.WelcomeMessage) {})
+	replayClient.OnEventChannelFollow(func(event twitch.EventChannelFollow, msg twitch.NotificationMessage) { follows <- event })
+
+	err = replayClient.ConnectWithContext(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer replayClient.Close()
+
+	_, err = replayServer.WaitForSession(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	replayer := replayServer.Replayer(frames)
+	replayer.Speed = 0
+	err = replayer.Run(ctx)
+	assert.NoError(t, err)
+
+	select {
+	case event := <-follows:
+		assert.Equal(t, "follower", event.UserLogin)
+	case <-ctx.Done():
+		t.Fatal("follow was not replayed")
+	}
+}
@@ -0,0 +1,619 @@
+// Package eventsubtest runs an in-process EventSub websocket server and a
+// fake helix /eventsub/subscriptions endpoint for testing code built on
+// twitch.Client.
+package eventsubtest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/google/uuid"
+	"github.com/joeyak/go-twitch-eventsub/v3"
+)
+
+const (
+	DefaultKeepaliveTimeout = 10 * time.Second
+	DefaultMaxTotalCost     = 10000
+
+	websocketPath    = "/ws"
+	subscriptionPath = "/eventsub/subscriptions"
+)
+
+var ErrNoSession = fmt.Errorf("no connected session")
+
+type session struct {
+	id   string
+	conn *websocket.Conn
+	// wrote is signaled on every message so keepalives are only sent after
+	// a quiet period like twitch does.
+	wrote chan struct{}
+}
+
+// Server speaks the EventSub websocket protocol to clients connecting to
+// WebsocketUrl, and keeps the subscriptions created through
+// SubscriptionUrl. Sessions keep their subscriptions across reconnects,
+// and the subscriptions of a disconnected session are disabled.
+type Server struct {
+	WebsocketUrl    string
+	SubscriptionUrl string
+
+	// KeepaliveTimeout is sent in the welcome message, a keepalive is sent
+	// when nothing else was sent for that long.
+	KeepaliveTimeout time.Duration
+
+	listener      net.Listener
+	server        *http.Server
+	mu            sync.Mutex
+	sessions      map[string]*session
+	subscriptions []twitch.PayloadSubscription
+	changed       chan struct{}
+}
+
+// NewServer starts a server on a random local port.
+func NewServer() (*Server, error) {
+	return NewServerWithAddress("127.0.0.1:0")
+}
+
+func NewServerWithAddress(address string) (*Server, error) {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("could not listen on %s: %w", address, err)
+	}
+
+	s := &Server{
+		WebsocketUrl:     fmt.Sprintf("ws://%s%s", listener.Addr(), websocketPath),
+		SubscriptionUrl:  fmt.Sprintf("http://%s%s", listener.Addr(), subscriptionPath),
+		KeepaliveTimeout: DefaultKeepaliveTimeout,
+		listener:         listener,
+		sessions:         map[string]*session{},
+		changed:          make(chan struct{}),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(websocketPath, s.handleWebsocket)
+	mux.HandleFunc(subscriptionPath, s.handleSubscriptions)
+	s.server = &http.Server{Handler: mux}
+
+	go s.server.Serve(listener)
+	return s, nil
+}
+
+// Client returns a client that connects to the server and subscribes
+// through it.
+func (s *Server) Client() *twitch.Client {
+	client := twitch.NewClientWithUrl(s.WebsocketUrl)
+	client.SubscriptionUrl = s.SubscriptionUrl
+	return client
+}
+
+// Close closes the connected sessions and stops the server.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	for _, session := range s.sessions {
+		session.conn.Close(websocket.StatusGoingAway, "server closed")
+	}
+	s.mu.Unlock()
+
+	return s.server.Close()
+}
+
+// Sessions returns the IDs of the connected sessions.
+func (s *Server) Sessions() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := make([]string, 0, len(s.sessions))
+	for id := range s.sessions {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// Subscriptions returns every subscription created on the server,
+// including disabled ones.
+func (s *Server) Subscriptions() []twitch.PayloadSubscription {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]twitch.PayloadSubscription(nil), s.subscriptions...)
+}
+
+// WaitForSession waits until a session is connected and returns its ID.
+func (s *Server) WaitForSession(ctx context.Context) (string, error) {
+	var id string
+	err := s.wait(ctx, func() bool {
+		for sessionID := range s.sessions {
+			id = sessionID
+			return true
+		}
+		return false
+	})
+	return id, err
+}
+
+// WaitForSubscription waits until an enabled subscription to the event is
+// created.
+func (s *Server) WaitForSubscription(ctx context.Context, event twitch.EventSubscription) (twitch.PayloadSubscription, error) {
+	var subscription twitch.PayloadSubscription
+	err := s.wait(ctx, func() bool {
+		for _, sub := range s.subscriptions {
+			if sub.Type == event && sub.SubscriptionStatus() == twitch.SubscriptionStatusEnabled {
+				subscription = sub
+				return true
+			}
+		}
+		return false
+	})
+	return subscription, err
+}
+
+// Emit sends the event to the sessions subscribed to it, or to every
+// session when none is, so tests don't have to subscribe first. The event
+// must be the type the library decodes the subscription into, or raw json.
+func (s *Server) Emit(event twitch.EventSubscription, payload any) error {
+	data, err := marshalEvent(event, payload)
+	if err != nil {
+		return err
+	}
+	return s.emit(newSubscription(event, "", nil, ""), data)
+}
+
+// emit sends the event to the sessions subscribed to the type of template,
+// or to every session with a copy of template.
+func (s *Server) emit(template twitch.PayloadSubscription, data json.RawMessage) error {
+	event := template.Type
+
+	s.mu.Lock()
+	targets := map[*session]twitch.PayloadSubscription{}
+	for _, sub := range s.subscriptions {
+		session, ok := s.sessions[sub.Transport.SessionID]
+		if ok && sub.Type == event && sub.SubscriptionStatus() == twitch.SubscriptionStatusEnabled {
+			targets[session] = sub
+		}
+	}
+	if len(targets) == 0 {
+		for _, session := range s.sessions {
+			sub := template
+			sub.Transport = twitch.SubscriptionTransport{Method: "websocket", SessionID: session.id}
+			targets[session] = sub
+		}
+	}
+	s.mu.Unlock()
+
+	if len(targets) == 0 {
+		return fmt.Errorf("could not emit %s: %w", event, ErrNoSession)
+	}
+
+	for session, sub := range targets {
+		var message twitch.NotificationMessage
+		message.Metadata = newMetadata("notification")
+		message.Payload.Subscription = sub
+		message.Payload.Event = &data
+
+		err := s.write(session, message)
+		if err != nil {
+			return fmt.Errorf("could not emit %s: %w", event, err)
+		}
+	}
+	return nil
+}
+
+// Keepalive sends a keepalive message to every session.
+func (s *Server) Keepalive() error {
+	for _, session := range s.connected() {
+		err := s.write(session, twitch.KeepAliveMessage{Metadata: newMetadata("session_keepalive")})
+		if err != nil {
+			return fmt.Errorf("could not send keepalive: %w", err)
+		}
+	}
+	return nil
+}
+
+// Reconnect asks every session to reconnect. The sessions keep their ID and
+// subscriptions on the new connection.
+func (s *Server) Reconnect() error {
+	for _, session := range s.connected() {
+		var message twitch.ReconnectMessage
+		message.Metadata = newMetadata("session_reconnect")
+		message.Payload.Session = twitch.PayloadSession{
+			ID:           session.id,
+			Status:       "reconnecting",
+			ConnectedAt:  time.Now(),
+			ReconnectUrl: s.WebsocketUrl + "?" + url.Values{"reconnect": {session.id}}.Encode(),
+		}
+
+		err := s.write(session, message)
+		if err != nil {
+			return fmt.Errorf("could not send reconnect: %w", err)
+		}
+	}
+	return nil
+}
+
+// Revoke disables the subscription with the status and sends the
+// revocation to its session.
+func (s *Server) Revoke(subscriptionID string, status twitch.SubscriptionStatus) error {
+	s.mu.Lock()
+	var revoked *twitch.PayloadSubscription
+	for i := range s.subscriptions {
+		if s.subscriptions[i].ID == subscriptionID {
+			s.subscriptions[i].Status = string(status)
+			revoked = &s.subscriptions[i]
+			break
+		}
+	}
+	if revoked == nil {
+		s.mu.Unlock()
+		return fmt.Errorf("could not revoke subscription %s: not found", subscriptionID)
+	}
+
+	var message twitch.RevokeMessage
+	message.Metadata = newMetadata("revocation")
+	message.Payload.Subscription = *revoked
+	session, ok := s.sessions[revoked.Transport.SessionID]
+	s.notify()
+	s.mu.Unlock()
+
+	if !ok {
+		return nil
+	}
+	return s.write(session, message)
+}
+
+// Disconnect closes the connection of every session with the status, like
+// twitch does with 4000 to 4007 when something goes wrong.
+func (s *Server) Disconnect(status websocket.StatusCode, reason string) error {
+	for _, session := range s.connected() {
+		err := session.conn.Close(status, reason)
+		if err != nil && websocket.CloseStatus(err) == -1 {
+			return fmt.Errorf("could not disconnect session %s: %w", session.id, err)
+		}
+	}
+	return nil
+}
+
+func (s *Server) handleWebsocket(w http.ResponseWriter, r *http.Request) {
+	conn, err := websocket.Accept(w, r, nil)
+	if err != nil {
+		return
+	}
+
+	s.mu.Lock()
+	id := r.URL.Query().Get("reconnect")
+	if _, ok := s.sessions[id]; !ok {
+		id = strings.ReplaceAll(uuid.NewString(), "-", "")
+	}
+	current := &session{id: id, conn: conn, wrote: make(chan struct{}, 1)}
+	s.sessions[id] = current
+	keepalive := s.KeepaliveTimeout
+	s.notify()
+	s.mu.Unlock()
+
+	var welcome twitch.WelcomeMessage
+	welcome.Metadata = newMetadata("session_welcome")
+	welcome.Payload.Session = twitch.PayloadSession{
+		ID:                      current.id,
+		Status:                  "connected",
+		ConnectedAt:             time.Now(),
+		KeepaliveTimeoutSeconds: int(keepalive / time.Second),
+	}
+	err = s.write(current, welcome)
+	if err != nil {
+		s.disconnected(current)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	go s.keepalive(ctx, current, keepalive)
+
+	// clients don't send anything, reading returns when the connection
+	// is closed
+	for {
+		_, _, err = conn.Read(ctx)
+		if err != nil {
+			break
+		}
+	}
+	s.disconnected(current)
+}
+
+func (s *Server) keepalive(ctx context.Context, session *session, timeout time.Duration) {
+	if timeout <= 0 {
+		return
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-session.wrote:
+		case <-timer.C:
+			err := s.write(session, twitch.KeepAliveMessage{Metadata: newMetadata("session_keepalive")})
+			if err != nil {
+				return
+			}
+		}
+
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(timeout)
+	}
+}
+
+// disconnected removes the session and disables its subscriptions unless
+// it already reconnected on another connection.
+func (s *Server) disconnected(closed *session) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.sessions[closed.id] != closed {
+		return
+	}
+	delete(s.sessions, closed.id)
+
+	for i, sub := range s.subscriptions {
+		if sub.Transport.SessionID == closed.id && sub.SubscriptionStatus() == twitch.SubscriptionStatusEnabled {
+			s.subscriptions[i].Status = string(twitch.SubscriptionStatusWebsocketDisconnected)
+		}
+	}
+	s.notify()
+}
+
+func (s *Server) handleSubscriptions(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		s.createSubscription(w, r)
+	case http.MethodGet:
+		s.listSubscriptions(w, r)
+	case http.MethodDelete:
+		s.deleteSubscription(w, r)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (s *Server) createSubscription(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "could not read body")
+		return
+	}
+
+	var request twitch.SubscriptionRequest
+	err = json.Unmarshal(body, &request)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+
+	if _, ok := twitch.SubMetadata()[request.Type]; !ok {
+		writeError(w, http.StatusBadRequest, "unknown subscription type "+string(request.Type))
+		return
+	}
+	if request.Transport.Method != "websocket" {
+		writeError(w, http.StatusBadRequest, "only the websocket transport is supported")
+		return
+	}
+
+	s.mu.Lock()
+	if _, ok := s.sessions[request.Transport.SessionID]; !ok {
+		s.mu.Unlock()
+		writeError(w, http.StatusBadRequest, "unknown session "+request.Transport.SessionID)
+		return
+	}
+	for _, sub := range s.subscriptions {
+		if sub.SubscriptionStatus() == twitch.SubscriptionStatusEnabled && sub.Transport.SessionID == request.Transport.SessionID &&
+			sub.Type == request.Type && sub.Version == request.Version && reflect.DeepEqual(sub.Condition, request.Condition) {
+			s.mu.Unlock()
+			writeError(w, http.StatusConflict, "subscription already exists")
+			return
+		}
+	}
+
+	sub := newSubscription(request.Type, request.Version, request.Condition, request.Transport.SessionID)
+	s.subscriptions = append(s.subscriptions, sub)
+	response := s.response([]twitch.PayloadSubscription{sub})
+	s.notify()
+	s.mu.Unlock()
+
+	writeJson(w, http.StatusAccepted, response)
+}
+
+func (s *Server) listSubscriptions(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	s.mu.Lock()
+	var subscriptions []twitch.PayloadSubscription
+	for _, sub := range s.subscriptions {
+		if status := query.Get("status"); status != "" && string(sub.Status) != status {
+			continue
+		}
+		if event := query.Get("type"); event != "" && string(sub.Type) != event {
+			continue
+		}
+		if id := query.Get("subscription_id"); id != "" && sub.ID != id {
+			continue
+		}
+		subscriptions = append(subscriptions, sub)
+	}
+	response := s.response(subscriptions)
+	s.mu.Unlock()
+
+	writeJson(w, http.StatusOK, response)
+}
+
+func (s *Server) deleteSubscription(w http.ResponseWriter, r *http.Request) {
+	id := r.URL.Query().Get("id")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, sub := range s.subscriptions {
+		if sub.ID == id {
+			s.subscriptions = append(s.subscriptions[:i:i], s.subscriptions[i+1:]...)
+			s.notify()
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "subscription not found")
+}
+
+// response must be called with mu held.
+func (s *Server) response(data []twitch.PayloadSubscription) twitch.SubscribeResponse {
+	response := twitch.SubscribeResponse{
+		Data:         data,
+		MaxTotalCost: DefaultMaxTotalCost,
+	}
+	if response.Data == nil {
+		response.Data = []twitch.PayloadSubscription{}
+	}
+	for _, sub := range s.subscriptions {
+		if sub.SubscriptionStatus() == twitch.SubscriptionStatusEnabled {
+			response.Total++
+			response.TotalCost += sub.Cost
+		}
+	}
+	return response
+}
+
+func (s *Server) connected() []*session {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sessions := make([]*session, 0, len(s.sessions))
+	for _, session := range s.sessions {
+		sessions = append(sessions, session)
+	}
+	return sessions
+}
+
+func (s *Server) write(session *session, message any) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("could not marshal message: %w", err)
+	}
+	return s.writeRaw(session, data)
+}
+
+func (s *Server) writeRaw(session *session, data []byte) error {
+	select {
+	case session.wrote <- struct{}{}:
+	default:
+	}
+
+	return session.conn.Write(context.Background(), websocket.MessageText, data)
+}
+
+// notify must be called with mu held.
+func (s *Server) notify() {
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+func (s *Server) wait(ctx context.Context, done func() bool) error {
+	for {
+		s.mu.Lock()
+		ok := done()
+		changed := s.changed
+		s.mu.Unlock()
+
+		if ok {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+func marshalEvent(event twitch.EventSubscription, payload any) (json.RawMessage, error) {
+	switch payload := payload.(type) {
+	case json.RawMessage:
+		return payload, nil
+	case []byte:
+		return payload, nil
+	}
+
+	metadata, ok := twitch.SubMetadata()[event]
+	if !ok {
+		return nil, fmt.Errorf("could not emit %s: unknown subscription type", event)
+	}
+
+	expected := reflect.TypeOf(metadata.EventGen()).Elem()
+	if reflect.TypeOf(payload) != expected {
+		return nil, fmt.Errorf("could not emit %s: expected %s, got %T", event, expected, payload)
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal %s event: %w", event, err)
+	}
+	return data, nil
+}
+
+func newSubscription(event twitch.EventSubscription, version string, condition map[string]string, sessionID string) twitch.PayloadSubscription {
+	if version == "" {
+		version = twitch.SubMetadata()[event].Version
+	}
+	if condition == nil {
+		condition = map[string]string{}
+	}
+
+	return twitch.PayloadSubscription{
+		SubscriptionRequest: twitch.SubscriptionRequest{
+			Type:      event,
+			Version:   version,
+			Condition: condition,
+			Transport: twitch.SubscriptionTransport{
+				Method:    "websocket",
+				SessionID: sessionID,
+			},
+		},
+		ID:       uuid.NewString(),
+		Status:   string(twitch.SubscriptionStatusEnabled),
+		CreateAt: time.Now(),
+	}
+}
+
+func newMetadata(messageType string) twitch.MessageMetadata {
+	return twitch.MessageMetadata{
+		MessageID:        uuid.NewString(),
+		MessageType:      messageType,
+		MessageTimestamp: time.Now(),
+	}
+}
+
+func writeJson(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJson(w, status, map[string]any{
+		"error":   http.StatusText(status),
+		"status":  status,
+		"message": message,
+	})
+}
@@ -0,0 +1,179 @@
+package eventsubtest_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/joeyak/go-twitch-eventsub/v3"
+	"github.com/joeyak/go-twitch-eventsub/v3/eventsubtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func newServer(t *testing.T) *eventsubtest.Server {
+	server, err := eventsubtest.NewServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { server.Close() })
+	return server
+}
+
+func TestServerEmit(t *testing.T) {
+	server := newServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	follows := make(chan twitch.EventChannelFollow, 2)
+	client := server.Client()
+	client.OnError(func(err error) { t.Errorf("client registered an error: %v", err) })
+	client.OnWelcome(func(message twitch.WelcomeMessage) {
+		_, err := client.Subscribe(ctx, twitch.SubscribeRequest{
+			Event:     twitch.SubChannelFollow,
+			Condition: map[string]string{"broadcaster_user_id": "1337"},
+		})
+		if err != nil {
+			t.Errorf("could not subscribe: %v", err)
+		}
+	})
+	client.OnEventChannelFollow(func(event twitch.EventChannelFollow, msg twitch.NotificationMessage) {
+		follows <- event
+	})
+
+	err := client.ConnectWithContext(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	subscription, err := server.WaitForSubscription(ctx, twitch.SubChannelFollow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, client.SessionID(), subscription.Transport.SessionID)
+	assert.Equal(t, "1337", subscription.Condition["broadcaster_user_id"])
+
+	err = server.Emit(twitch.SubChannelFollow, twitch.EventChannelFollow{User: twitch.User{UserLogin: "follower"}})
+	assert.NoError(t, err)
+
+	err = server.Emit(twitch.SubChannelFollow, twitch.EventChannelRaid{})
+	assert.Error(t, err)
+
+	select {
+	case event := <-follows:
+		assert.Equal(t, "follower", event.UserLogin)
+	case <-ctx.Done():
+		t.Fatal("follow was not received")
+	}
+}
+
+func TestServerReconnect(t *testing.T) {
+	server := newServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	reconnects := make(chan twitch.ReconnectMessage, 1)
+	raids := make(chan twitch.EventChannelRaid, 1)
+	client := server.Client()
+	client.OnWelcome(func(message twitch.WelcomeMessage) {})
+	client.OnReconnect(func(message twitch.ReconnectMessage) { reconnects <- message })
+	client.OnEventChannelRaid(func(event twitch.EventChannelRaid, msg twitch.NotificationMessage) { raids <- event })
+
+	err := client.ConnectWithContext(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	sessionID, err := server.WaitForSession(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = server.Reconnect()
+	assert.NoError(t, err)
+
+	select {
+	case message := <-reconnects:
+		assert.Equal(t, sessionID, message.Payload.Session.ID)
+	case <-ctx.Done():
+		t.Fatal("reconnect was not received")
+	}
+
+	// events are received on the new connection with the same session
+	assert.Eventually(t, func() bool {
+		sessions := server.Sessions()
+		return len(sessions) == 1 && sessions[0] == sessionID
+	}, time.Second, 10*time.Millisecond)
+
+	err = server.Emit(twitch.SubChannelRaid, twitch.EventChannelRaid{Viewers: 42})
+	assert.NoError(t, err)
+
+	select {
+	case event := <-raids:
+		assert.Equal(t, 42, event.Viewers)
+	case <-ctx.Done():
+		t.Fatal("raid was not received")
+	}
+}
+
+func TestServerDisconnect(t *testing.T) {
+	server := newServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	revokes := make(chan twitch.RevokeMessage, 1)
+	readErrors := make(chan error, 1)
+	client := server.Client()
+	client.OnWelcome(func(message twitch.WelcomeMessage) {
+		client.Subscribe(ctx, twitch.SubscribeRequest{Event: twitch.SubStreamOnline})
+		client.Subscribe(ctx, twitch.SubscribeRequest{Event: twitch.SubStreamOffline})
+	})
+	client.OnRevoke(func(message twitch.RevokeMessage) { revokes <- message })
+
+	err := client.ConnectWithContext(ctx, func(ctx context.Context, err error) { readErrors <- err })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	online, err := server.WaitForSubscription(ctx, twitch.SubStreamOnline)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = server.WaitForSubscription(ctx, twitch.SubStreamOffline)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = server.Revoke(online.ID, twitch.SubscriptionStatusAuthorizationRevoked)
+	assert.NoError(t, err)
+
+	select {
+	case message := <-revokes:
+		assert.Equal(t, twitch.SubscriptionStatusAuthorizationRevoked, message.Payload.Subscription.SubscriptionStatus())
+	case <-ctx.Done():
+		t.Fatal("revocation was not received")
+	}
+
+	err = server.Disconnect(websocket.StatusCode(4003), "connection unused")
+	assert.NoError(t, err)
+
+	select {
+	case err := <-readErrors:
+		assert.Equal(t, websocket.StatusCode(4003), websocket.CloseStatus(err))
+	case <-ctx.Done():
+		t.Fatal("disconnect was not received")
+	}
+
+	assert.Eventually(t, func() bool { return len(server.Sessions()) == 0 }, time.Second, 10*time.Millisecond)
+
+	statuses := map[twitch.EventSubscription]twitch.SubscriptionStatus{}
+	for _, subscription := range server.Subscriptions() {
+		statuses[subscription.Type] = subscription.SubscriptionStatus()
+	}
+	assert.Equal(t, map[twitch.EventSubscription]twitch.SubscriptionStatus{
+		twitch.SubStreamOnline:  twitch.SubscriptionStatusAuthorizationRevoked,
+		twitch.SubStreamOffline: twitch.SubscriptionStatusWebsocketDisconnected,
+	}, statuses)
+}
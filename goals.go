@@ -0,0 +1,234 @@
+package twitch
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+const DefaultVelocityWindow = 5 * time.Minute
+
+// ProgressSample is an amount seen at a point in time, used to estimate
+// when a goal will be reached.
+type ProgressSample struct {
+	At     time.Time `json:"at"`
+	Amount int       `json:"amount"`
+}
+
+// addSample appends the sample and drops samples older than the window.
+func addSample(samples []ProgressSample, sample ProgressSample, window time.Duration) []ProgressSample {
+	if sample.At.IsZero() {
+		return samples
+	}
+
+	samples = append(append([]ProgressSample(nil), samples...), sample)
+	sort.SliceStable(samples, func(i, j int) bool { return samples[i].At.Before(samples[j].At) })
+
+	last := samples[len(samples)-1].At
+	for len(samples) > 2 && last.Sub(samples[0].At) > window {
+		samples = samples[1:]
+	}
+	return samples
+}
+
+// estimateReached returns when target will be reached at the velocity of the
+// samples. There's no estimate without progress or once the target is reached.
+func estimateReached(samples []ProgressSample, current, target int) (time.Time, bool) {
+	if len(samples) < 2 || current >= target {
+		return time.Time{}, false
+	}
+
+	first, last := samples[0], samples[len(samples)-1]
+	elapsed := last.At.Sub(first.At)
+	gained := last.Amount - first.Amount
+	if elapsed <= 0 || gained <= 0 {
+		return time.Time{}, false
+	}
+
+	remaining := time.Duration(float64(target-current) / float64(gained) * float64(elapsed))
+	return last.At.Add(remaining), true
+}
+
+func percent(current, target int) float64 {
+	if target <= 0 {
+		return 0
+	}
+	if current >= target {
+		return 1
+	}
+	return float64(current) / float64(target)
+}
+
+type GoalState struct {
+	Broadcaster
+
+	ID            string           `json:"id"`
+	Type          GoalType         `json:"type"`
+	Description   string           `json:"description"`
+	CurrentAmount int              `json:"current_amount"`
+	TargetAmount  int              `json:"target_amount"`
+	IsAchieved    bool             `json:"is_achieved"`
+	StartedAt     time.Time        `json:"started_at"`
+	EndedAt       time.Time        `json:"ended_at"`
+	UpdatedAt     time.Time        `json:"updated_at"`
+	Samples       []ProgressSample `json:"samples"`
+}
+
+func (s GoalState) IsEnded() bool {
+	return !s.EndedAt.IsZero()
+}
+
+// IsReached reports whether the target was reached, even if the end event
+// was missed.
+func (s GoalState) IsReached() bool {
+	return s.IsAchieved || (s.TargetAmount > 0 && s.CurrentAmount >= s.TargetAmount)
+}
+
+// Percent returns the progress towards the target from 0 to 1.
+func (s GoalState) Percent() float64 {
+	return percent(s.CurrentAmount, s.TargetAmount)
+}
+
+// ETA estimates when the target will be reached from the recent progress.
+func (s GoalState) ETA() (time.Time, bool) {
+	if s.IsEnded() {
+		return time.Time{}, false
+	}
+	return estimateReached(s.Samples, s.CurrentAmount, s.TargetAmount)
+}
+
+// GoalTracker keeps the state of creator goals. It can be snapshot with
+// json.Marshal and restored with json.Unmarshal so overlays keep their
+// state across restarts.
+type GoalTracker struct {
+	VelocityWindow time.Duration
+
+	notifier
+
+	goals     map[string]GoalState
+	onChange  func(state GoalState)
+	onReached func(state GoalState)
+}
+
+func NewGoalTracker() *GoalTracker {
+	return &GoalTracker{
+		VelocityWindow: DefaultVelocityWindow,
+		goals:          map[string]GoalState{},
+	}
+}
+
+func (t *GoalTracker) OnChange(callback func(state GoalState)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.onChange = callback
+}
+
+// OnReached is called once per goal when its target is reached.
+func (t *GoalTracker) OnReached(callback func(state GoalState)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.onReached = callback
+}
+
+func (t *GoalTracker) Goal(id string) (GoalState, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	state, ok := t.goals[id]
+	return state, ok
+}
+
+// Goals returns the goals of the broadcaster that didn't end, oldest first.
+func (t *GoalTracker) Goals(broadcasterID string) []GoalState {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var goals []GoalState
+	for _, state := range t.goals {
+		if state.BroadcasterUserId == broadcasterID && !state.IsEnded() {
+			goals = append(goals, state)
+		}
+	}
+	sort.Slice(goals, func(i, j int) bool { return goals[i].StartedAt.Before(goals[j].StartedAt) })
+	return goals
+}
+
+func (t *GoalTracker) MarshalJSON() ([]byte, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return json.Marshal(t.goals)
+}
+
+func (t *GoalTracker) UnmarshalJSON(data []byte) error {
+	goals := map[string]GoalState{}
+	err := json.Unmarshal(data, &goals)
+	if err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.goals = goals
+	return nil
+}
+
+func (t *GoalTracker) HandleBegin(event EventChannelGoalBegin, msg NotificationMessage) {
+	t.update(GoalState{
+		Broadcaster:   event.Broadcaster,
+		ID:            event.ID,
+		Type:          event.Type,
+		Description:   event.Description,
+		CurrentAmount: event.CurrentAmount,
+		TargetAmount:  event.TargetAmount,
+		StartedAt:     event.StartedAt,
+	}, msg)
+}
+
+func (t *GoalTracker) HandleProgress(event EventChannelGoalProgress, msg NotificationMessage) {
+	t.HandleBegin(EventChannelGoalBegin(event), msg)
+}
+
+func (t *GoalTracker) HandleEnd(event EventChannelGoalEnd, msg NotificationMessage) {
+	endedAt := event.EndedAt
+	if endedAt.IsZero() {
+		endedAt = msg.Metadata.MessageTimestamp
+	}
+
+	t.update(GoalState{
+		Broadcaster:   event.Broadcaster,
+		ID:            event.ID,
+		Type:          event.Type,
+		Description:   event.Description,
+		CurrentAmount: event.CurrentAmount,
+		TargetAmount:  event.TargetAmount,
+		IsAchieved:    event.IsAchieved,
+		StartedAt:     event.StartedAt,
+		EndedAt:       endedAt,
+	}, msg)
+}
+
+func (t *GoalTracker) update(state GoalState, msg NotificationMessage) {
+	state.UpdatedAt = msg.Metadata.MessageTimestamp
+
+	t.mu.Lock()
+	if t.goals == nil {
+		t.goals = map[string]GoalState{}
+	}
+
+	old, ok := t.goals[state.ID]
+	if ok && isStale(old.UpdatedAt, state.UpdatedAt, old.IsEnded(), state.IsEnded()) {
+		t.mu.Unlock()
+		return
+	}
+	state.Samples = addSample(old.Samples, ProgressSample{state.UpdatedAt, state.CurrentAmount}, t.VelocityWindow)
+	t.goals[state.ID] = state
+
+	onChange, onReached := t.onChange, t.onReached
+	t.unlockAndNotify(func() {
+		if onChange != nil {
+			onChange(state)
+		}
+		if onReached != nil && state.IsReached() && !old.IsReached() {
+			onReached(state)
+		}
+	})
+}
@@ -0,0 +1,75 @@
+package twitch_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/joeyak/go-twitch-eventsub/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGoalTracker(t *testing.T) {
+	tracker := twitch.NewGoalTracker()
+
+	var reached []twitch.GoalState
+	tracker.OnReached(func(state twitch.GoalState) { reached = append(reached, state) })
+
+	now := time.Now()
+	goal := twitch.EventChannelGoalBegin{
+		Broadcaster:   twitch.Broadcaster{BroadcasterUserId: "141981764"},
+		ID:            "goal",
+		Type:          twitch.GoalTypeSubscription,
+		CurrentAmount: 100,
+		TargetAmount:  220,
+	}
+	tracker.HandleBegin(goal, newNotificationAt(now))
+
+	goal.CurrentAmount = 160
+	tracker.HandleProgress(twitch.EventChannelGoalProgress(goal), newNotificationAt(now.Add(time.Minute)))
+
+	state, ok := tracker.Goal("goal")
+	assert.True(t, ok)
+	assert.InDelta(t, 160.0/220.0, state.Percent(), 0.0001)
+
+	eta, ok := state.ETA()
+	assert.True(t, ok)
+	assert.Equal(t, now.Add(2*time.Minute), eta)
+
+	// the target is reached without an end event
+	goal.CurrentAmount = 230
+	tracker.HandleProgress(twitch.EventChannelGoalProgress(goal), newNotificationAt(now.Add(2*time.Minute)))
+	tracker.HandleEnd(twitch.EventChannelGoalEnd{EventChannelGoalBegin: goal, IsAchieved: true}, newNotificationAt(now.Add(3*time.Minute)))
+
+	assert.Len(t, reached, 1)
+	assert.Empty(t, tracker.Goals("141981764"))
+
+	data, err := json.Marshal(tracker)
+	assert.NoError(t, err)
+
+	restored := twitch.NewGoalTracker()
+	err = json.Unmarshal(data, restored)
+	assert.NoError(t, err)
+
+	state, ok = restored.Goal("goal")
+	assert.True(t, ok)
+	assert.True(t, state.IsReached())
+	assert.True(t, state.IsEnded())
+	assert.Equal(t, 230, state.CurrentAmount)
+}
+
+func TestGoalTrackerLateProgress(t *testing.T) {
+	tracker := twitch.NewGoalTracker()
+
+	now := time.Now()
+	goal := twitch.EventChannelGoalBegin{ID: "goal", CurrentAmount: 180, TargetAmount: 220}
+	tracker.HandleEnd(twitch.EventChannelGoalEnd{EventChannelGoalBegin: goal}, newNotificationAt(now))
+
+	goal.CurrentAmount = 120
+	tracker.HandleProgress(twitch.EventChannelGoalProgress(goal), newNotificationAt(now.Add(time.Second)))
+
+	state, _ := tracker.Goal("goal")
+	assert.True(t, state.IsEnded())
+	assert.False(t, state.IsReached())
+	assert.Equal(t, 180, state.CurrentAmount)
+}
@@ -0,0 +1,310 @@
+package twitch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	twitchHelixUrl = "https://api.twitch.tv/helix"
+
+	defaultHelixRetries = 3
+	defaultHelixBackoff = 500 * time.Millisecond
+)
+
+var ErrNoHelixClient = fmt.Errorf("no helix client")
+
+// DefaultRateLimiter is shared by every helix call the library makes. It
+// keeps a separate bucket for every client ID and token.
+var DefaultRateLimiter = NewRateLimiter()
+
+type RateLimit struct {
+	Limit     int
+	Remaining int
+	Reset     time.Time
+}
+
+// RateLimiter follows the token buckets twitch describes in the Ratelimit-*
+// headers, one per client ID and token. Requests wait for their bucket to
+// refill when it is empty, rate limited requests are retried after the
+// reset, and idempotent requests are retried with backoff on server errors.
+type RateLimiter struct {
+	MaxRetries int
+	Backoff    time.Duration
+
+	mu      sync.Mutex
+	buckets map[string]*RateLimit
+}
+
+func NewRateLimiter() *RateLimiter {
+	return &RateLimiter{
+		MaxRetries: defaultHelixRetries,
+		Backoff:    defaultHelixBackoff,
+		buckets:    map[string]*RateLimit{},
+	}
+}
+
+func bucketKey(clientID, authorization string) string {
+	return clientID + "\x00" + authorization
+}
+
+func requestBucketKey(request *http.Request) string {
+	return bucketKey(request.Header.Get("Client-Id"), request.Header.Get("Authorization"))
+}
+
+// isIdempotent reports if requests with the method can be retried safely.
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// RateLimit returns the budget of the client ID and token from the last
+// helix response. Remaining is -1 before any response was seen.
+func (l *RateLimiter) RateLimit(clientID, accessToken string) RateLimit {
+	return l.rateLimit(bucketKey(clientID, fmt.Sprintf("Bearer %s", accessToken)))
+}
+
+func (l *RateLimiter) rateLimit(key string) RateLimit {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	limit, ok := l.buckets[key]
+	if !ok {
+		return RateLimit{Remaining: -1}
+	}
+	return *limit
+}
+
+// Do sends the request and returns the response with its body already read
+// and closed. Only transport errors are returned as errors, the status code
+// of the final attempt is left for the caller to check.
+func (l *RateLimiter) Do(ctx context.Context, request *http.Request, idempotent bool) (*http.Response, []byte, error) {
+	return l.do(ctx, request, idempotent, l.MaxRetries)
+}
+
+// do is Do retrying at most maxRetries times instead of MaxRetries.
+func (l *RateLimiter) do(ctx context.Context, request *http.Request, idempotent bool, maxRetries int) (*http.Response, []byte, error) {
+	key := requestBucketKey(request)
+
+	for attempt := 0; ; attempt++ {
+		err := l.take(ctx, key)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		req, err := cloneRequest(ctx, request)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		resp, body, err := send(req)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		l.update(key, resp)
+
+		if attempt >= maxRetries {
+			return resp, body, nil
+		}
+
+		var wait time.Duration
+		switch {
+		case resp.StatusCode == http.StatusTooManyRequests:
+			wait = time.Until(l.rateLimit(key).Reset)
+			if wait <= 0 {
+				wait = l.Backoff
+			}
+		case resp.StatusCode >= 500 && idempotent:
+			wait = l.Backoff << attempt
+		default:
+			return resp, body, nil
+		}
+
+		err = sleep(ctx, wait)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+}
+
+func (l *RateLimiter) take(ctx context.Context, key string) error {
+	for {
+		l.mu.Lock()
+		limit, ok := l.buckets[key]
+		if !ok || limit.Remaining > 0 || !time.Now().Before(limit.Reset) {
+			if ok && limit.Remaining > 0 {
+				limit.Remaining--
+			}
+			l.mu.Unlock()
+			return nil
+		}
+		wait := time.Until(limit.Reset)
+		l.mu.Unlock()
+
+		err := sleep(ctx, wait)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (l *RateLimiter) update(key string, resp *http.Response) {
+	limit, limitErr := strconv.Atoi(resp.Header.Get("Ratelimit-Limit"))
+	remaining, remainingErr := strconv.Atoi(resp.Header.Get("Ratelimit-Remaining"))
+	reset, resetErr := strconv.ParseInt(resp.Header.Get("Ratelimit-Reset"), 10, 64)
+	if limitErr != nil && remainingErr != nil && resetErr != nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	bucket, ok := l.buckets[key]
+	if !ok {
+		bucket = &RateLimit{}
+		l.buckets[key] = bucket
+	}
+	if limitErr == nil {
+		bucket.Limit = limit
+	}
+	if remainingErr == nil {
+		bucket.Remaining = remaining
+	}
+	if resetErr == nil {
+		bucket.Reset = time.Unix(reset, 0)
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		bucket.Remaining = 0
+	}
+}
+
+// send sends the request once without rate limiting and returns the
+// response with its body already read and closed.
+func send(request *http.Request) (*http.Response, []byte, error) {
+	resp, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not read response body: %w", err)
+	}
+	return resp, body, nil
+}
+
+func cloneRequest(ctx context.Context, request *http.Request) (*http.Request, error) {
+	req := request.Clone(ctx)
+	if request.GetBody == nil {
+		return req, nil
+	}
+
+	body, err := request.GetBody()
+	if err != nil {
+		return nil, fmt.Errorf("could not get request body: %w", err)
+	}
+	req.Body = body
+	return req, nil
+}
+
+func sleep(ctx context.Context, duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("could not wait for rate limit: %w", ctx.Err())
+	case <-timer.C:
+		return nil
+	}
+}
+
+// HelixClient calls the helix endpoints used by the helpers of the library.
+// The access token needs the scopes of the endpoints that are called.
+type HelixClient struct {
+	ClientID    string
+	AccessToken string
+	BaseUrl     string
+	RateLimiter *RateLimiter
+}
+
+func NewHelixClient(clientID, accessToken string) *HelixClient {
+	return NewHelixClientWithUrl(twitchHelixUrl, clientID, accessToken)
+}
+
+func NewHelixClientWithUrl(baseUrl, clientID, accessToken string) *HelixClient {
+	return &HelixClient{
+		ClientID:    clientID,
+		AccessToken: accessToken,
+		BaseUrl:     baseUrl,
+		RateLimiter: DefaultRateLimiter,
+	}
+}
+
+// do sends the body as json to the endpoint and unmarshals the response
+// into result when it's not nil. Responses that are not 2xx are returned as
+// a ResponseError.
+func (h *HelixClient) do(ctx context.Context, method, endpoint string, query url.Values, body, result any) error {
+	var reader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("could not convert request to json: %w", err)
+		}
+		reader = bytes.NewReader(b)
+	}
+
+	address := strings.TrimSuffix(h.BaseUrl, "/") + endpoint
+	if len(query) > 0 {
+		address += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, address, reader)
+	if err != nil {
+		return fmt.Errorf("could not create new request: %w", err)
+	}
+
+	req.Header.Set("Client-Id", h.ClientID)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", h.AccessToken))
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	limiter := h.RateLimiter
+	if limiter == nil {
+		limiter = DefaultRateLimiter
+	}
+
+	resp, respBody, err := limiter.Do(ctx, req, isIdempotent(method))
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return newResponseError(resp, respBody)
+	}
+
+	if result == nil || len(respBody) == 0 {
+		return nil
+	}
+
+	err = json.Unmarshal(respBody, result)
+	if err != nil {
+		return fmt.Errorf("could not unmarshal response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package twitch_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/joeyak/go-twitch-eventsub/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func newStatusServer(t *testing.T, statuses ...int) (string, *atomic.Int32) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var calls atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		call := int(calls.Add(1)) - 1
+		status := statuses[len(statuses)-1]
+		if call < len(statuses) {
+			status = statuses[call]
+		}
+
+		w.Header().Set("Ratelimit-Limit", "800")
+		w.Header().Set("Ratelimit-Remaining", fmt.Sprint(799-call))
+		w.Header().Set("Ratelimit-Reset", fmt.Sprint(time.Now().Unix()))
+		w.WriteHeader(status)
+	})
+	go http.Serve(listener, mux)
+
+	return fmt.Sprintf("http://%s", listener.Addr().String()), &calls
+}
+
+func newTestRateLimiter() *twitch.RateLimiter {
+	limiter := twitch.NewRateLimiter()
+	limiter.Backoff = time.Millisecond
+	return limiter
+}
+
+func TestRateLimiterRetries(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		Name       string
+		Method     string
+		Idempotent bool
+		Statuses   []int
+		Expected   int
+		Calls      int32
+	}{
+		{"Ok", http.MethodGet, true, []int{200}, 200, 1},
+		{"RateLimited", http.MethodPost, false, []int{429, 202}, 202, 2},
+		{"ServerErrorIdempotent", http.MethodGet, true, []int{503, 500, 200}, 200, 3},
+		{"ServerErrorNotIdempotent", http.MethodPost, false, []int{503, 200}, 503, 1},
+		{"ServerErrorDelete", http.MethodDelete, true, []int{502, 204}, 204, 2},
+		{"ServerErrorExhausted", http.MethodGet, true, []int{500}, 500, 4},
+		{"BadRequest", http.MethodGet, true, []int{400, 200}, 400, 1},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+
+			url, calls := newStatusServer(t, tc.Statuses...)
+			limiter := newTestRateLimiter()
+
+			request, err := http.NewRequest(tc.Method, url, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			resp, _, err := limiter.Do(context.Background(), request, tc.Idempotent)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.Expected, resp.StatusCode)
+			assert.Equal(t, tc.Calls, calls.Load())
+		})
+	}
+}
+
+func TestRateLimiterRemaining(t *testing.T) {
+	t.Parallel()
+
+	url, _ := newStatusServer(t, 200)
+	limiter := newTestRateLimiter()
+	assert.Equal(t, -1, limiter.RateLimit("client", "token").Remaining)
+
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	request.Header.Set("Client-Id", "client")
+	request.Header.Set("Authorization", "Bearer token")
+
+	_, _, err = limiter.Do(context.Background(), request, true)
+	assert.NoError(t, err)
+
+	limit := limiter.RateLimit("client", "token")
+	assert.Equal(t, 800, limit.Limit)
+	assert.Equal(t, 799, limit.Remaining)
+
+	// other tokens have their own bucket
+	assert.Equal(t, -1, limiter.RateLimit("client", "other").Remaining)
+}
+
+func TestRateLimiterBuckets(t *testing.T) {
+	t.Parallel()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Ratelimit-Limit", "800")
+		w.Header().Set("Ratelimit-Remaining", "0")
+		w.Header().Set("Ratelimit-Reset", fmt.Sprint(time.Now().Add(time.Hour).Unix()))
+	})
+	go http.Serve(listener, mux)
+
+	limiter := newTestRateLimiter()
+	send := func(ctx context.Context, token string) error {
+		request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("http://%s", listener.Addr()), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		request.Header.Set("Client-Id", "client")
+		request.Header.Set("Authorization", "Bearer "+token)
+
+		_, _, err = limiter.Do(ctx, request, true)
+		return err
+	}
+
+	assert.NoError(t, send(context.Background(), "exhausted"))
+
+	// the exhausted token waits for its reset, other tokens don't
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, send(ctx, "exhausted"), context.DeadlineExceeded)
+	assert.NoError(t, send(context.Background(), "other"))
+}
+
+func TestRateLimiterContext(t *testing.T) {
+	t.Parallel()
+
+	url, calls := newStatusServer(t, 503)
+	limiter := newTestRateLimiter()
+	limiter.Backoff = time.Hour
+
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, _, err = limiter.Do(ctx, request, true)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, int32(1), calls.Load())
+}
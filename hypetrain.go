@@ -0,0 +1,249 @@
+package twitch
+
+import (
+	"sort"
+	"time"
+)
+
+type HypeTrainState struct {
+	Broadcaster
+
+	ID               string                  `json:"id"`
+	Level            int                     `json:"level"`
+	Total            int                     `json:"total"`
+	Progress         int                     `json:"progress"`
+	Goal             int                     `json:"goal"`
+	LastContribution HypeTrainContribution   `json:"last_contribution"`
+	Contributions    []HypeTrainContribution `json:"contributions"`
+	StartedAt        time.Time               `json:"started_at"`
+	ExpiresAt        time.Time               `json:"expires_at"`
+	EndedAt          time.Time               `json:"ended_at"`
+	CooldownEndsAt   time.Time               `json:"cooldown_ends_at"`
+	UpdatedAt        time.Time               `json:"updated_at"`
+}
+
+func (s HypeTrainState) IsEnded() bool {
+	return !s.EndedAt.IsZero()
+}
+
+// Percent returns how far the current level is towards its goal from 0 to 1.
+func (s HypeTrainState) Percent() float64 {
+	if s.Goal <= 0 {
+		return 0
+	}
+	if s.Progress >= s.Goal {
+		return 1
+	}
+	return float64(s.Progress) / float64(s.Goal)
+}
+
+// Remaining returns the time left before the train expires, or 0 once it
+// ended or expired.
+func (s HypeTrainState) Remaining(now time.Time) time.Duration {
+	if s.IsEnded() || !now.Before(s.ExpiresAt) {
+		return 0
+	}
+	return s.ExpiresAt.Sub(now)
+}
+
+// OnCooldown reports whether a new train can't start yet.
+func (s HypeTrainState) OnCooldown(now time.Time) bool {
+	return s.IsEnded() && now.Before(s.CooldownEndsAt)
+}
+
+func (s HypeTrainState) CooldownRemaining(now time.Time) time.Duration {
+	if !s.OnCooldown(now) {
+		return 0
+	}
+	return s.CooldownEndsAt.Sub(now)
+}
+
+// Leaderboard returns up to n contributors of the whole train with the
+// highest total for the contribution type. An empty type adds up the
+// contributions of every type per user. A negative n returns all of them.
+func (s HypeTrainState) Leaderboard(contributionType HypeTrainContributionType, n int) []HypeTrainContribution {
+	var leaderboard []HypeTrainContribution
+	index := map[string]int{}
+	for _, contribution := range s.Contributions {
+		if contributionType != "" && contribution.Type != contributionType {
+			continue
+		}
+		if contributionType == "" {
+			contribution.Type = ""
+		}
+
+		i, ok := index[contribution.UserID]
+		if ok && contributionType == "" {
+			leaderboard[i].Total += contribution.Total
+			continue
+		}
+		index[contribution.UserID] = len(leaderboard)
+		leaderboard = append(leaderboard, contribution)
+	}
+
+	sortContributions(leaderboard)
+	if n >= 0 && len(leaderboard) > n {
+		leaderboard = leaderboard[:n]
+	}
+	return leaderboard
+}
+
+func sortContributions(contributions []HypeTrainContribution) {
+	sort.SliceStable(contributions, func(i, j int) bool {
+		return contributions[i].Total > contributions[j].Total
+	})
+}
+
+// mergeContributions keeps the highest total seen for each user and type.
+// Top contributions are totals for the train, so the highest one is the
+// most recent.
+func mergeContributions(contributions []HypeTrainContribution, seen ...HypeTrainContribution) []HypeTrainContribution {
+	merged := append([]HypeTrainContribution(nil), contributions...)
+	for _, contribution := range seen {
+		if contribution.UserID == "" {
+			continue
+		}
+
+		found := false
+		for i := range merged {
+			if merged[i].UserID == contribution.UserID && merged[i].Type == contribution.Type {
+				if contribution.Total > merged[i].Total {
+					merged[i].Total = contribution.Total
+				}
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, contribution)
+		}
+	}
+
+	sortContributions(merged)
+	return merged
+}
+
+// HypeTrainTracker folds the hype train events of each broadcaster into a
+// HypeTrainState. The state of an ended train is kept for its cooldown.
+type HypeTrainTracker struct {
+	notifier
+
+	trains    map[string]HypeTrainState
+	onChange  func(state HypeTrainState)
+	onLevelUp func(state HypeTrainState, previousLevel int)
+	onEnd     func(state HypeTrainState)
+}
+
+func NewHypeTrainTracker() *HypeTrainTracker {
+	return &HypeTrainTracker{
+		trains: map[string]HypeTrainState{},
+	}
+}
+
+func (t *HypeTrainTracker) OnChange(callback func(state HypeTrainState)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.onChange = callback
+}
+
+func (t *HypeTrainTracker) OnLevelUp(callback func(state HypeTrainState, previousLevel int)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.onLevelUp = callback
+}
+
+func (t *HypeTrainTracker) OnEnd(callback func(state HypeTrainState)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.onEnd = callback
+}
+
+// Train returns the current or last train of the broadcaster.
+func (t *HypeTrainTracker) Train(broadcasterID string) (HypeTrainState, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	state, ok := t.trains[broadcasterID]
+	return state, ok
+}
+
+func (t *HypeTrainTracker) HandleBegin(event EventChannelHypeTrainBegin, msg NotificationMessage) {
+	t.update(HypeTrainState{
+		Broadcaster:      event.Broadcaster,
+		ID:               event.Id,
+		Level:            event.Level,
+		Total:            event.Total,
+		Progress:         event.Progress,
+		Goal:             event.Goal,
+		LastContribution: event.LastContribution,
+		StartedAt:        event.StartedAt,
+		ExpiresAt:        event.ExpiresAt,
+	}, append(append([]HypeTrainContribution(nil), event.TopContributions...), event.LastContribution), msg)
+}
+
+func (t *HypeTrainTracker) HandleProgress(event EventChannelHypeTrainProgress, msg NotificationMessage) {
+	begin := event.EventChannelHypeTrainBegin
+	begin.Level = event.Level
+	t.HandleBegin(begin, msg)
+}
+
+func (t *HypeTrainTracker) HandleEnd(event EventChannelHypeTrainEnd, msg NotificationMessage) {
+	endedAt := event.EndedAt
+	if endedAt.IsZero() {
+		endedAt = msg.Metadata.MessageTimestamp
+	}
+
+	t.update(HypeTrainState{
+		Broadcaster:    event.Broadcaster,
+		ID:             event.Id,
+		Level:          event.Level,
+		Total:          event.Total,
+		StartedAt:      event.StartedAt,
+		ExpiresAt:      event.ExpiresAt,
+		EndedAt:        endedAt,
+		CooldownEndsAt: event.CooldownEndsAt,
+	}, event.TopContributions, msg)
+}
+
+func (t *HypeTrainTracker) update(state HypeTrainState, contributions []HypeTrainContribution, msg NotificationMessage) {
+	state.UpdatedAt = msg.Metadata.MessageTimestamp
+
+	t.mu.Lock()
+	old, ok := t.trains[state.BroadcasterUserId]
+	sameTrain := ok && old.ID == state.ID
+	switch {
+	case sameTrain && isStale(old.UpdatedAt, state.UpdatedAt, old.IsEnded(), state.IsEnded()):
+		t.mu.Unlock()
+		return
+	case ok && !sameTrain && state.UpdatedAt.Before(old.UpdatedAt):
+		t.mu.Unlock()
+		return
+	}
+
+	previousLevel := 0
+	if sameTrain {
+		previousLevel = old.Level
+		state.Contributions = old.Contributions
+		if state.IsEnded() {
+			state.Progress, state.Goal = old.Progress, old.Goal
+			state.LastContribution = old.LastContribution
+		}
+		if state.ExpiresAt.IsZero() {
+			state.ExpiresAt = old.ExpiresAt
+		}
+	}
+	state.Contributions = mergeContributions(state.Contributions, contributions...)
+	t.trains[state.BroadcasterUserId] = state
+
+	onChange, onLevelUp, onEnd := t.onChange, t.onLevelUp, t.onEnd
+	t.unlockAndNotify(func() {
+		if onChange != nil {
+			onChange(state)
+		}
+		if onLevelUp != nil && sameTrain && state.Level > previousLevel {
+			onLevelUp(state, previousLevel)
+		}
+		if onEnd != nil && state.IsEnded() && !(sameTrain && old.IsEnded()) {
+			onEnd(state)
+		}
+	})
+}
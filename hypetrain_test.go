@@ -0,0 +1,79 @@
+package twitch_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/joeyak/go-twitch-eventsub/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHypeTrainTracker(t *testing.T) {
+	tracker := twitch.NewHypeTrainTracker()
+
+	var levelUps []int
+	tracker.OnLevelUp(func(state twitch.HypeTrainState, previousLevel int) {
+		levelUps = append(levelUps, previousLevel, state.Level)
+	})
+
+	var ended []twitch.HypeTrainState
+	tracker.OnEnd(func(state twitch.HypeTrainState) { ended = append(ended, state) })
+
+	now := time.Now()
+	broadcaster := twitch.Broadcaster{BroadcasterUserId: "1337"}
+	pogchamp := twitch.User{UserID: "123", UserLogin: "pogchamp"}
+	kappa := twitch.User{UserID: "456", UserLogin: "kappa"}
+
+	tracker.HandleBegin(twitch.EventChannelHypeTrainBegin{
+		Broadcaster:      broadcaster,
+		Id:               "train",
+		Level:            1,
+		Progress:         100,
+		Goal:             200,
+		LastContribution: twitch.HypeTrainContribution{User: kappa, Type: twitch.HypeTrainContributionTypeSubscription, Total: 500},
+		ExpiresAt:        now.Add(5 * time.Minute),
+	}, newNotificationAt(now))
+
+	progress := twitch.EventChannelHypeTrainProgress{Level: 2}
+	progress.Broadcaster = broadcaster
+	progress.Id = "train"
+	progress.Progress = 50
+	progress.Goal = 400
+	progress.ExpiresAt = now.Add(5 * time.Minute)
+	progress.TopContributions = []twitch.HypeTrainContribution{
+		{User: pogchamp, Type: twitch.HypeTrainContributionTypeBits, Total: 300},
+		{User: pogchamp, Type: twitch.HypeTrainContributionTypeSubscription, Total: 500},
+	}
+	tracker.HandleProgress(progress, newNotificationAt(now.Add(time.Second)))
+
+	state, ok := tracker.Train("1337")
+	assert.True(t, ok)
+	assert.Equal(t, []int{1, 2}, levelUps)
+	assert.Equal(t, 0.125, state.Percent())
+	assert.Equal(t, 4*time.Minute, state.Remaining(now.Add(time.Minute)))
+
+	leaderboard := state.Leaderboard("", -1)
+	assert.Len(t, leaderboard, 2)
+	assert.Equal(t, "pogchamp", leaderboard[0].UserLogin)
+	assert.Equal(t, 800, leaderboard[0].Total)
+	assert.Equal(t, "kappa", state.Leaderboard(twitch.HypeTrainContributionTypeSubscription, 1)[0].UserLogin)
+
+	tracker.HandleEnd(twitch.EventChannelHypeTrainEnd{
+		Broadcaster:    broadcaster,
+		Id:             "train",
+		Level:          2,
+		EndedAt:        now.Add(time.Minute),
+		CooldownEndsAt: now.Add(time.Hour),
+	}, newNotificationAt(now.Add(time.Minute)))
+
+	// late progress is dropped once the train ended
+	tracker.HandleProgress(progress, newNotificationAt(now.Add(2*time.Minute)))
+
+	state, _ = tracker.Train("1337")
+	assert.Len(t, ended, 1)
+	assert.True(t, state.IsEnded())
+	assert.Equal(t, time.Duration(0), state.Remaining(now.Add(time.Minute)))
+	assert.True(t, state.OnCooldown(now.Add(time.Minute)))
+	assert.Equal(t, 30*time.Minute, state.CooldownRemaining(now.Add(30*time.Minute)))
+	assert.False(t, state.OnCooldown(now.Add(2*time.Hour)))
+}
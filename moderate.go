@@ -0,0 +1,374 @@
+package twitch
+
+import (
+	"strings"
+)
+
+type ModerateAction string
+
+const (
+	ModerateActionBan                 ModerateAction = "ban"
+	ModerateActionTimeout             ModerateAction = "timeout"
+	ModerateActionUnban               ModerateAction = "unban"
+	ModerateActionUntimeout           ModerateAction = "untimeout"
+	ModerateActionClear               ModerateAction = "clear"
+	ModerateActionEmoteOnly           ModerateAction = "emoteonly"
+	ModerateActionEmoteOnlyOff        ModerateAction = "emoteonlyoff"
+	ModerateActionFollowers           ModerateAction = "followers"
+	ModerateActionFollowersOff        ModerateAction = "followersoff"
+	ModerateActionUniqueChat          ModerateAction = "uniquechat"
+	ModerateActionUniqueChatOff       ModerateAction = "uniquechatoff"
+	ModerateActionSlow                ModerateAction = "slow"
+	ModerateActionSlowOff             ModerateAction = "slowoff"
+	ModerateActionSubscribers         ModerateAction = "subscribers"
+	ModerateActionSubscribersOff      ModerateAction = "subscribersoff"
+	ModerateActionRaid                ModerateAction = "raid"
+	ModerateActionUnraid              ModerateAction = "unraid"
+	ModerateActionDelete              ModerateAction = "delete"
+	ModerateActionVip                 ModerateAction = "vip"
+	ModerateActionUnvip               ModerateAction = "unvip"
+	ModerateActionMod                 ModerateAction = "mod"
+	ModerateActionUnmod               ModerateAction = "unmod"
+	ModerateActionAddBlockedTerm      ModerateAction = "add_blocked_term"
+	ModerateActionAddPermittedTerm    ModerateAction = "add_permitted_term"
+	ModerateActionRemoveBlockedTerm   ModerateAction = "remove_blocked_term"
+	ModerateActionRemovePermittedTerm ModerateAction = "remove_permitted_term"
+	ModerateActionApproveUnbanRequest ModerateAction = "approve_unban_request"
+	ModerateActionDenyUnbanRequest    ModerateAction = "deny_unban_request"
+	ModerateActionWarn                ModerateAction = "warn"
+
+	ModerateActionSharedChatBan       ModerateAction = "shared_chat_ban"
+	ModerateActionSharedChatTimeout   ModerateAction = "shared_chat_timeout"
+	ModerateActionSharedChatUnban     ModerateAction = "shared_chat_unban"
+	ModerateActionSharedChatUntimeout ModerateAction = "shared_chat_untimeout"
+	ModerateActionSharedChatDelete    ModerateAction = "shared_chat_delete"
+)
+
+var moderateActions = []ModerateAction{
+	ModerateActionBan,
+	ModerateActionTimeout,
+	ModerateActionUnban,
+	ModerateActionUntimeout,
+	ModerateActionClear,
+	ModerateActionEmoteOnly,
+	ModerateActionEmoteOnlyOff,
+	ModerateActionFollowers,
+	ModerateActionFollowersOff,
+	ModerateActionUniqueChat,
+	ModerateActionUniqueChatOff,
+	ModerateActionSlow,
+	ModerateActionSlowOff,
+	ModerateActionSubscribers,
+	ModerateActionSubscribersOff,
+	ModerateActionRaid,
+	ModerateActionUnraid,
+	ModerateActionDelete,
+	ModerateActionVip,
+	ModerateActionUnvip,
+	ModerateActionMod,
+	ModerateActionUnmod,
+	ModerateActionAddBlockedTerm,
+	ModerateActionAddPermittedTerm,
+	ModerateActionRemoveBlockedTerm,
+	ModerateActionRemovePermittedTerm,
+	ModerateActionApproveUnbanRequest,
+	ModerateActionDenyUnbanRequest,
+	ModerateActionWarn,
+	ModerateActionSharedChatBan,
+	ModerateActionSharedChatTimeout,
+	ModerateActionSharedChatUnban,
+	ModerateActionSharedChatUntimeout,
+	ModerateActionSharedChatDelete,
+}
+
+func ModerateActions() []ModerateAction {
+	return append([]ModerateAction(nil), moderateActions...)
+}
+
+func (a ModerateAction) IsValid() bool {
+	return isEnumValue(moderateActions, a)
+}
+
+func (a ModerateAction) String() string {
+	return string(a)
+}
+
+func (a ModerateAction) IsSharedChat() bool {
+	return strings.HasPrefix(string(a), sharedChatPrefix)
+}
+
+// Base returns the action without the shared chat prefix.
+func (a ModerateAction) Base() ModerateAction {
+	return ModerateAction(strings.TrimPrefix(string(a), sharedChatPrefix))
+}
+
+func (a ModerateAction) sharedChat(isSharedChat bool) ModerateAction {
+	if isSharedChat {
+		return sharedChatPrefix + a
+	}
+	return a
+}
+
+// ModerateDetail is one of the Moderate types returned by
+// EventChannelModerate.Detail. Shared chat actions use the same type as their
+// regular action with IsSharedChat set.
+type ModerateDetail interface {
+	ModerateAction() ModerateAction
+}
+
+type ModerateBan struct {
+	Ban
+	IsSharedChat bool
+}
+
+func (d ModerateBan) ModerateAction() ModerateAction {
+	return ModerateActionBan.sharedChat(d.IsSharedChat)
+}
+
+type ModerateTimeout struct {
+	Timeout
+	IsSharedChat bool
+}
+
+func (d ModerateTimeout) ModerateAction() ModerateAction {
+	return ModerateActionTimeout.sharedChat(d.IsSharedChat)
+}
+
+type ModerateUnban struct {
+	User
+	IsSharedChat bool
+}
+
+func (d ModerateUnban) ModerateAction() ModerateAction {
+	return ModerateActionUnban.sharedChat(d.IsSharedChat)
+}
+
+type ModerateUntimeout struct {
+	User
+	IsSharedChat bool
+}
+
+func (d ModerateUntimeout) ModerateAction() ModerateAction {
+	return ModerateActionUntimeout.sharedChat(d.IsSharedChat)
+}
+
+type ModerateDelete struct {
+	DeletedMessage
+	IsSharedChat bool
+}
+
+func (d ModerateDelete) ModerateAction() ModerateAction {
+	return ModerateActionDelete.sharedChat(d.IsSharedChat)
+}
+
+type ModerateVip struct {
+	User
+}
+
+func (d ModerateVip) ModerateAction() ModerateAction { return ModerateActionVip }
+
+type ModerateUnvip struct {
+	User
+}
+
+func (d ModerateUnvip) ModerateAction() ModerateAction { return ModerateActionUnvip }
+
+type ModerateMod struct {
+	User
+}
+
+func (d ModerateMod) ModerateAction() ModerateAction { return ModerateActionMod }
+
+type ModerateUnmod struct {
+	User
+}
+
+func (d ModerateUnmod) ModerateAction() ModerateAction { return ModerateActionUnmod }
+
+type ModerateRaid struct {
+	Raid
+}
+
+func (d ModerateRaid) ModerateAction() ModerateAction { return ModerateActionRaid }
+
+type ModerateUnraid struct {
+	User
+}
+
+func (d ModerateUnraid) ModerateAction() ModerateAction { return ModerateActionUnraid }
+
+type ModerateFollowers struct {
+	Followers
+}
+
+func (d ModerateFollowers) ModerateAction() ModerateAction { return ModerateActionFollowers }
+
+type ModerateSlow struct {
+	SlowMode
+}
+
+func (d ModerateSlow) ModerateAction() ModerateAction { return ModerateActionSlow }
+
+type ModerateWarn struct {
+	Warning
+}
+
+func (d ModerateWarn) ModerateAction() ModerateAction { return ModerateActionWarn }
+
+type ModerateAutomodTerms struct {
+	AutomodTerms
+}
+
+func (d ModerateAutomodTerms) ModerateAction() ModerateAction {
+	return ModerateAction(d.Action + "_" + d.List + "_term")
+}
+
+type ModerateUnbanRequest struct {
+	UnbanRequest
+}
+
+func (d ModerateUnbanRequest) ModerateAction() ModerateAction {
+	if d.IsApproved {
+		return ModerateActionApproveUnbanRequest
+	}
+	return ModerateActionDenyUnbanRequest
+}
+
+// ModerateChatMode is a chat mode action without a payload, like emoteonly
+// or slowoff.
+type ModerateChatMode struct {
+	Action ModerateAction
+}
+
+func (d ModerateChatMode) ModerateAction() ModerateAction { return d.Action }
+
+type ModerateClear struct{}
+
+func (d ModerateClear) ModerateAction() ModerateAction { return ModerateActionClear }
+
+// Detail returns the payload of the event's Action, or nil when the action
+// is unknown or its payload is missing.
+func (e EventChannelModerate) Detail() ModerateDetail {
+	shared := e.Action.IsSharedChat()
+
+	switch e.Action.Base() {
+	case ModerateActionBan:
+		if v, ok := pick(shared, e.Ban, e.SharedChatBan); ok {
+			return ModerateBan{v, shared}
+		}
+	case ModerateActionTimeout:
+		if v, ok := pick(shared, e.Timeout, e.SharedChatTimeout); ok {
+			return ModerateTimeout{v, shared}
+		}
+	case ModerateActionUnban:
+		if v, ok := pick(shared, e.Unban, e.SharedChatUnban); ok {
+			return ModerateUnban{v, shared}
+		}
+	case ModerateActionUntimeout:
+		if v, ok := pick(shared, e.Untimeout, e.SharedChatUntimeout); ok {
+			return ModerateUntimeout{v, shared}
+		}
+	case ModerateActionDelete:
+		if v, ok := pick(shared, e.Delete, e.SharedChatDelete); ok {
+			return ModerateDelete{v, shared}
+		}
+	}
+
+	if shared {
+		return nil
+	}
+
+	switch e.Action {
+	case ModerateActionVip:
+		if e.Vip != nil {
+			return ModerateVip{*e.Vip}
+		}
+	case ModerateActionUnvip:
+		if e.Unvip != nil {
+			return ModerateUnvip{*e.Unvip}
+		}
+	case ModerateActionMod:
+		if e.Mod != nil {
+			return ModerateMod{*e.Mod}
+		}
+	case ModerateActionUnmod:
+		if e.Unmod != nil {
+			return ModerateUnmod{*e.Unmod}
+		}
+	case ModerateActionRaid:
+		if e.Raid != nil {
+			return ModerateRaid{*e.Raid}
+		}
+	case ModerateActionUnraid:
+		if e.Unraid != nil {
+			return ModerateUnraid{*e.Unraid}
+		}
+	case ModerateActionFollowers:
+		if e.Followers != nil {
+			return ModerateFollowers{*e.Followers}
+		}
+	case ModerateActionSlow:
+		if e.Slow != nil {
+			return ModerateSlow{*e.Slow}
+		}
+	case ModerateActionWarn:
+		if e.Warn != nil {
+			return ModerateWarn{*e.Warn}
+		}
+	case ModerateActionAddBlockedTerm, ModerateActionAddPermittedTerm, ModerateActionRemoveBlockedTerm, ModerateActionRemovePermittedTerm:
+		if e.AutomodTerms != nil {
+			return ModerateAutomodTerms{*e.AutomodTerms}
+		}
+	case ModerateActionApproveUnbanRequest, ModerateActionDenyUnbanRequest:
+		if e.UnbanRequest != nil {
+			return ModerateUnbanRequest{*e.UnbanRequest}
+		}
+	case ModerateActionEmoteOnly, ModerateActionEmoteOnlyOff, ModerateActionFollowersOff, ModerateActionUniqueChat,
+		ModerateActionUniqueChatOff, ModerateActionSlowOff, ModerateActionSubscribers, ModerateActionSubscribersOff:
+		return ModerateChatMode{e.Action}
+	case ModerateActionClear:
+		return ModerateClear{}
+	}
+
+	return nil
+}
+
+// IsSharedChat reports if the action was taken in another channel of a
+// shared chat session.
+func (e EventChannelModerate) IsSharedChat() bool {
+	return e.Action.IsSharedChat()
+}
+
+// TargetUser returns the user the action was taken against. Raids return
+// the raided channel.
+func (e EventChannelModerate) TargetUser() (User, bool) {
+	switch detail := e.Detail().(type) {
+	case ModerateBan:
+		return detail.User, true
+	case ModerateTimeout:
+		return detail.User, true
+	case ModerateUnban:
+		return detail.User, true
+	case ModerateUntimeout:
+		return detail.User, true
+	case ModerateDelete:
+		return detail.User, true
+	case ModerateVip:
+		return detail.User, true
+	case ModerateUnvip:
+		return detail.User, true
+	case ModerateMod:
+		return detail.User, true
+	case ModerateUnmod:
+		return detail.User, true
+	case ModerateRaid:
+		return detail.User, true
+	case ModerateUnraid:
+		return detail.User, true
+	case ModerateWarn:
+		return detail.User, true
+	case ModerateUnbanRequest:
+		return detail.User, true
+	}
+	return User{}, false
+}
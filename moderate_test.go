@@ -0,0 +1,76 @@
+package twitch_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/joeyak/go-twitch-eventsub/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModerateDetail(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Json     string
+		Expected twitch.ModerateDetail
+		Target   string
+	}{
+		{
+			"Ban",
+			`{"action":"ban","ban":{"user_id":"1","user_login":"troll","reason":"spam"}}`,
+			twitch.ModerateBan{Ban: twitch.Ban{User: twitch.User{UserID: "1", UserLogin: "troll"}, Reason: "spam"}},
+			"troll",
+		},
+		{
+			"SharedChatUntimeout",
+			`{"action":"shared_chat_untimeout","shared_chat_untimeout":{"user_id":"2","user_login":"viewer"}}`,
+			twitch.ModerateUntimeout{User: twitch.User{UserID: "2", UserLogin: "viewer"}, IsSharedChat: true},
+			"viewer",
+		},
+		{
+			"AddBlockedTerm",
+			`{"action":"add_blocked_term","automod_terms":{"action":"add","list":"blocked","terms":["bad"]}}`,
+			twitch.ModerateAutomodTerms{AutomodTerms: twitch.AutomodTerms{Action: "add", List: "blocked", Terms: []string{"bad"}}},
+			"",
+		},
+		{
+			"DenyUnbanRequest",
+			`{"action":"deny_unban_request","unban_request":{"user_login":"troll","is_approved":false}}`,
+			twitch.ModerateUnbanRequest{UnbanRequest: twitch.UnbanRequest{User: twitch.User{UserLogin: "troll"}}},
+			"troll",
+		},
+		{"EmoteOnly", `{"action":"emoteonly"}`, twitch.ModerateChatMode{Action: twitch.ModerateActionEmoteOnly}, ""},
+		{"Clear", `{"action":"clear"}`, twitch.ModerateClear{}, ""},
+		{"MissingPayload", `{"action":"ban","ban":null}`, nil, ""},
+		{"Unknown", `{"action":"something_new"}`, nil, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			var event twitch.EventChannelModerate
+			err := json.Unmarshal([]byte(tc.Json), &event)
+			assert.NoError(t, err)
+
+			detail := event.Detail()
+			assert.Equal(t, tc.Expected, detail)
+			if detail != nil {
+				assert.Equal(t, event.Action, detail.ModerateAction())
+			}
+
+			target, ok := event.TargetUser()
+			assert.Equal(t, tc.Target != "", ok)
+			assert.Equal(t, tc.Target, target.UserLogin)
+		})
+	}
+}
+
+func TestModerateIsSharedChat(t *testing.T) {
+	assert.True(t, twitch.EventChannelModerate{Action: twitch.ModerateActionSharedChatBan}.IsSharedChat())
+	assert.False(t, twitch.EventChannelModerate{Action: twitch.ModerateActionBan}.IsSharedChat())
+	assert.Equal(t, twitch.ModerateActionDelete, twitch.ModerateActionSharedChatDelete.Base())
+
+	assert.True(t, twitch.ModerateActionSharedChatUntimeout.IsValid())
+	assert.False(t, twitch.ModerateAction("shared_chat_vip").IsValid())
+	assert.Equal(t, "emoteonlyoff", twitch.ModerateActionEmoteOnlyOff.String())
+	assert.Contains(t, twitch.ModerateActions(), twitch.ModerateActionWarn)
+}
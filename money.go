@@ -0,0 +1,155 @@
+package twitch
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+const CurrencyBits = "BITS"
+
+var (
+	ErrCurrencyMismatch = fmt.Errorf("currencies do not match")
+
+	currencySymbols = map[string]string{
+		"USD": "$",
+		"EUR": "€",
+		"GBP": "£",
+		"JPY": "¥",
+		"CNY": "CN¥",
+		"KRW": "₩",
+		"INR": "₹",
+		"BRL": "R$",
+		"CAD": "CA$",
+		"AUD": "A$",
+		"NZD": "NZ$",
+		"MXN": "MX$",
+		"CHF": "CHF ",
+		"SEK": "SEK ",
+		"NOK": "NOK ",
+		"DKK": "DKK ",
+		"PLN": "PLN ",
+	}
+)
+
+// Money is an exact amount in minor units, Value 550 with 2 decimal places
+// is 5.50.
+type Money struct {
+	Value         int    `json:"value"`
+	DecimalPlaces int    `json:"decimal_places"`
+	Currency      string `json:"currency"`
+}
+
+func Bits(bits int) Money {
+	return Money{Value: bits, Currency: CurrencyBits}
+}
+
+// Amount converts the value to a float which can lose precision.
+//
+// Deprecated: use Decimal, String or Add for exact amounts.
+func (m Money) Amount() float64 {
+	return float64(m.Value) / math.Pow10(m.DecimalPlaces)
+}
+
+func (m Money) IsZero() bool {
+	return m.Value == 0
+}
+
+// Decimal formats the amount without a currency, like 5.50.
+func (m Money) Decimal() string {
+	value := m.Value
+	sign := ""
+	if value < 0 {
+		sign = "-"
+		value = -value
+	}
+
+	digits := fmt.Sprint(value)
+	if m.DecimalPlaces <= 0 {
+		return sign + digits
+	}
+
+	if len(digits) <= m.DecimalPlaces {
+		digits = strings.Repeat("0", m.DecimalPlaces-len(digits)+1) + digits
+	}
+
+	split := len(digits) - m.DecimalPlaces
+	return sign + digits[:split] + "." + digits[split:]
+}
+
+// String formats the amount with its currency, like $5.50 or 12.00 XYZ.
+func (m Money) String() string {
+	if m.Currency == CurrencyBits {
+		return fmt.Sprintf("%s bits", m.Decimal())
+	}
+
+	symbol, ok := currencySymbols[m.Currency]
+	if !ok {
+		return strings.TrimSpace(fmt.Sprintf("%s %s", m.Decimal(), m.Currency))
+	}
+
+	if m.Value < 0 {
+		return "-" + symbol + Money{-m.Value, m.DecimalPlaces, m.Currency}.Decimal()
+	}
+	return symbol + m.Decimal()
+}
+
+// Rescale returns the same amount with more decimal places. Fewer decimal
+// places than the amount has are ignored since that would lose precision.
+func (m Money) Rescale(decimalPlaces int) Money {
+	for m.DecimalPlaces < decimalPlaces {
+		m.Value *= 10
+		m.DecimalPlaces++
+	}
+	return m
+}
+
+// Add sums two amounts of the same currency, using the larger number of
+// decimal places.
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+
+	m, other = m.Rescale(other.DecimalPlaces), other.Rescale(m.DecimalPlaces)
+	m.Value += other.Value
+	return m, nil
+}
+
+func (m Money) Sub(other Money) (Money, error) {
+	other.Value = -other.Value
+	return m.Add(other)
+}
+
+// Cmp returns -1, 0 or 1 when the amount is less than, equal to or greater
+// than other.
+func (m Money) Cmp(other Money) (int, error) {
+	diff, err := m.Sub(other)
+	if err != nil {
+		return 0, err
+	}
+
+	switch {
+	case diff.Value < 0:
+		return -1, nil
+	case diff.Value > 0:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+func SumMoney(amounts ...Money) (Money, error) {
+	if len(amounts) == 0 {
+		return Money{}, nil
+	}
+
+	total := amounts[0]
+	for _, amount := range amounts[1:] {
+		var err error
+		total, err = total.Add(amount)
+		if err != nil {
+			return Money{}, err
+		}
+	}
+	return total, nil
+}
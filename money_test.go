@@ -0,0 +1,98 @@
+package twitch_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/joeyak/go-twitch-eventsub/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMoneyString(t *testing.T) {
+	testCases := []struct {
+		Money    twitch.Money
+		Expected string
+	}{
+		{twitch.Money{Value: 550, DecimalPlaces: 2, Currency: "USD"}, "$5.50"},
+		{twitch.Money{Value: 5, DecimalPlaces: 2, Currency: "USD"}, "$0.05"},
+		{twitch.Money{Value: -1250, DecimalPlaces: 2, Currency: "EUR"}, "-€12.50"},
+		{twitch.Money{Value: 1000, DecimalPlaces: 0, Currency: "JPY"}, "¥1000"},
+		{twitch.Money{Value: 12345, DecimalPlaces: 4, Currency: "XYZ"}, "1.2345 XYZ"},
+		{twitch.Money{Value: 9999999999, DecimalPlaces: 1, Currency: "USD"}, "$999999999.9"},
+		{twitch.Bits(500), "500 bits"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Expected, func(t *testing.T) {
+			assert.Equal(t, tc.Expected, tc.Money.String())
+		})
+	}
+}
+
+func TestMoneyAdd(t *testing.T) {
+	testCases := []struct {
+		A, B     twitch.Money
+		Expected twitch.Money
+		Err      error
+	}{
+		{
+			twitch.Money{Value: 550, DecimalPlaces: 2, Currency: "USD"},
+			twitch.Money{Value: 50, DecimalPlaces: 2, Currency: "USD"},
+			twitch.Money{Value: 600, DecimalPlaces: 2, Currency: "USD"},
+			nil,
+		},
+		{
+			twitch.Money{Value: 1, DecimalPlaces: 1, Currency: "USD"},
+			twitch.Money{Value: 2, DecimalPlaces: 2, Currency: "USD"},
+			twitch.Money{Value: 12, DecimalPlaces: 2, Currency: "USD"},
+			nil,
+		},
+		{
+			twitch.Money{Value: 550, DecimalPlaces: 2, Currency: "USD"},
+			twitch.Money{Value: 550, DecimalPlaces: 2, Currency: "EUR"},
+			twitch.Money{},
+			twitch.ErrCurrencyMismatch,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%s+%s", tc.A, tc.B), func(t *testing.T) {
+			actual, err := tc.A.Add(tc.B)
+			assert.ErrorIs(t, err, tc.Err)
+			assert.Equal(t, tc.Expected, actual)
+		})
+	}
+}
+
+func TestMoneySumIsExact(t *testing.T) {
+	amounts := make([]twitch.Money, 10)
+	for i := range amounts {
+		amounts[i] = twitch.Money{Value: 10, DecimalPlaces: 2, Currency: "USD"}
+	}
+
+	total, err := twitch.SumMoney(amounts...)
+	assert.NoError(t, err)
+	assert.Equal(t, "$1.00", total.String())
+
+	cmp, err := total.Cmp(twitch.Money{Value: 1, Currency: "USD"})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, cmp)
+}
+
+func TestMoneyJson(t *testing.T) {
+	var donation twitch.ChatNotificationCharityDonation
+	err := json.Unmarshal([]byte(`{"charity_name":"example","amount":{"value":550,"decimal_place":2,"currency":"USD"}}`), &donation)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, donation.Amount.DecimalPlace)
+	assert.Equal(t, "$5.50", donation.Amount.Money().String())
+
+	// chat notifications keep their decimal_place spelling
+	data, err := json.Marshal(donation)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"charity_name":"example","amount":{"value":550,"decimal_place":2,"currency":"USD"}}`, string(data))
+
+	data, err = json.Marshal(donation.Amount.Money())
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"value":550,"decimal_places":2,"currency":"USD"}`, string(data))
+}
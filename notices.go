@@ -0,0 +1,256 @@
+package twitch
+
+import (
+	"strings"
+)
+
+type NoticeType string
+
+const (
+	NoticeTypeSub              NoticeType = "sub"
+	NoticeTypeResub            NoticeType = "resub"
+	NoticeTypeSubGift          NoticeType = "sub_gift"
+	NoticeTypeCommunitySubGift NoticeType = "community_sub_gift"
+	NoticeTypeGiftPaidUpgrade  NoticeType = "gift_paid_upgrade"
+	NoticeTypePrimePaidUpgrade NoticeType = "prime_paid_upgrade"
+	NoticeTypePayItForward     NoticeType = "pay_it_forward"
+	NoticeTypeRaid             NoticeType = "raid"
+	NoticeTypeUnraid           NoticeType = "unraid"
+	NoticeTypeAnnouncement     NoticeType = "announcement"
+	NoticeTypeBitsBadgeTier    NoticeType = "bits_badge_tier"
+	NoticeTypeCharityDonation  NoticeType = "charity_donation"
+
+	NoticeTypeSharedChatSub              NoticeType = "shared_chat_sub"
+	NoticeTypeSharedChatResub            NoticeType = "shared_chat_resub"
+	NoticeTypeSharedChatSubGift          NoticeType = "shared_chat_sub_gift"
+	NoticeTypeSharedChatCommunitySubGift NoticeType = "shared_chat_community_sub_gift"
+	NoticeTypeSharedChatGiftPaidUpgrade  NoticeType = "shared_chat_gift_paid_upgrade"
+	NoticeTypeSharedChatPrimePaidUpgrade NoticeType = "shared_chat_prime_paid_upgrade"
+	NoticeTypeSharedChatPayItForward     NoticeType = "shared_chat_pay_it_forward"
+	NoticeTypeSharedChatRaid             NoticeType = "shared_chat_raid"
+	NoticeTypeSharedChatAnnouncement     NoticeType = "shared_chat_announcement"
+)
+
+const sharedChatPrefix = "shared_chat_"
+
+var noticeTypes = []NoticeType{
+	NoticeTypeSub,
+	NoticeTypeResub,
+	NoticeTypeSubGift,
+	NoticeTypeCommunitySubGift,
+	NoticeTypeGiftPaidUpgrade,
+	NoticeTypePrimePaidUpgrade,
+	NoticeTypePayItForward,
+	NoticeTypeRaid,
+	NoticeTypeUnraid,
+	NoticeTypeAnnouncement,
+	NoticeTypeBitsBadgeTier,
+	NoticeTypeCharityDonation,
+	NoticeTypeSharedChatSub,
+	NoticeTypeSharedChatResub,
+	NoticeTypeSharedChatSubGift,
+	NoticeTypeSharedChatCommunitySubGift,
+	NoticeTypeSharedChatGiftPaidUpgrade,
+	NoticeTypeSharedChatPrimePaidUpgrade,
+	NoticeTypeSharedChatPayItForward,
+	NoticeTypeSharedChatRaid,
+	NoticeTypeSharedChatAnnouncement,
+}
+
+func NoticeTypes() []NoticeType {
+	return append([]NoticeType(nil), noticeTypes...)
+}
+
+func (t NoticeType) IsValid() bool {
+	return isEnumValue(noticeTypes, t)
+}
+
+func (t NoticeType) String() string {
+	return string(t)
+}
+
+func (t NoticeType) IsSharedChat() bool {
+	return strings.HasPrefix(string(t), sharedChatPrefix)
+}
+
+// Base returns the notice type without the shared chat prefix.
+func (t NoticeType) Base() NoticeType {
+	return NoticeType(strings.TrimPrefix(string(t), sharedChatPrefix))
+}
+
+func (t NoticeType) sharedChat(isSharedChat bool) NoticeType {
+	if isSharedChat {
+		return sharedChatPrefix + t
+	}
+	return t
+}
+
+// Notice is one of the Notice types returned by
+// EventChannelChatNotification.Notice. Shared chat notices use the same type
+// as their regular notice with IsSharedChat set.
+type Notice interface {
+	Type() NoticeType
+}
+
+type NoticeSub struct {
+	ChatNotificationSub
+	IsSharedChat bool
+}
+
+func (n NoticeSub) Type() NoticeType { return NoticeTypeSub.sharedChat(n.IsSharedChat) }
+
+type NoticeResub struct {
+	ChatNotificationResub
+	IsSharedChat bool
+}
+
+func (n NoticeResub) Type() NoticeType { return NoticeTypeResub.sharedChat(n.IsSharedChat) }
+
+type NoticeSubGift struct {
+	ChatNotificationSubGift
+	IsSharedChat bool
+}
+
+func (n NoticeSubGift) Type() NoticeType { return NoticeTypeSubGift.sharedChat(n.IsSharedChat) }
+
+type NoticeCommunitySubGift struct {
+	ChatNotificationCommunitySubGift
+	IsSharedChat bool
+}
+
+func (n NoticeCommunitySubGift) Type() NoticeType {
+	return NoticeTypeCommunitySubGift.sharedChat(n.IsSharedChat)
+}
+
+type NoticeGiftPaidUpgrade struct {
+	ChatNotificationGiftPaidUpgrade
+	IsSharedChat bool
+}
+
+func (n NoticeGiftPaidUpgrade) Type() NoticeType {
+	return NoticeTypeGiftPaidUpgrade.sharedChat(n.IsSharedChat)
+}
+
+type NoticePrimePaidUpgrade struct {
+	ChatNotificationPrimePaidUpgrade
+	IsSharedChat bool
+}
+
+func (n NoticePrimePaidUpgrade) Type() NoticeType {
+	return NoticeTypePrimePaidUpgrade.sharedChat(n.IsSharedChat)
+}
+
+type NoticePayItForward struct {
+	ChatNotificationPayItForward
+	IsSharedChat bool
+}
+
+func (n NoticePayItForward) Type() NoticeType {
+	return NoticeTypePayItForward.sharedChat(n.IsSharedChat)
+}
+
+type NoticeRaid struct {
+	ChatNotificationRaid
+	IsSharedChat bool
+}
+
+func (n NoticeRaid) Type() NoticeType { return NoticeTypeRaid.sharedChat(n.IsSharedChat) }
+
+type NoticeUnraid struct {
+	ChatNotificationUnraid
+}
+
+func (n NoticeUnraid) Type() NoticeType { return NoticeTypeUnraid }
+
+type NoticeAnnouncement struct {
+	ChatNotificationAnnouncement
+	IsSharedChat bool
+}
+
+func (n NoticeAnnouncement) Type() NoticeType {
+	return NoticeTypeAnnouncement.sharedChat(n.IsSharedChat)
+}
+
+type NoticeBitsBadgeTier struct {
+	ChatNotificationBitsBadgeTier
+}
+
+func (n NoticeBitsBadgeTier) Type() NoticeType { return NoticeTypeBitsBadgeTier }
+
+type NoticeCharityDonation struct {
+	ChatNotificationCharityDonation
+}
+
+func (n NoticeCharityDonation) Type() NoticeType { return NoticeTypeCharityDonation }
+
+func pick[T any](isSharedChat bool, regular, sharedChat *T) (T, bool) {
+	v := regular
+	if isSharedChat {
+		v = sharedChat
+	}
+	if v == nil {
+		var zero T
+		return zero, false
+	}
+	return *v, true
+}
+
+// Notice returns the payload of the notification's NoticeType, or nil when
+// the type is unknown or its payload is missing.
+func (e EventChannelChatNotification) Notice() Notice {
+	shared := e.NoticeType.IsSharedChat()
+
+	switch e.NoticeType.Base() {
+	case NoticeTypeSub:
+		if v, ok := pick(shared, e.Sub, e.SharedChatSub); ok {
+			return NoticeSub{v, shared}
+		}
+	case NoticeTypeResub:
+		if v, ok := pick(shared, e.Resub, e.SharedChatResub); ok {
+			return NoticeResub{v, shared}
+		}
+	case NoticeTypeSubGift:
+		if v, ok := pick(shared, e.SubGift, e.SharedChatSubGift); ok {
+			return NoticeSubGift{v, shared}
+		}
+	case NoticeTypeCommunitySubGift:
+		if v, ok := pick(shared, e.CommunitySubGift, e.SharedChatCommunitySubGift); ok {
+			return NoticeCommunitySubGift{v, shared}
+		}
+	case NoticeTypeGiftPaidUpgrade:
+		if v, ok := pick(shared, e.GiftPaidUpgrade, e.SharedChatGiftPaidUpgrade); ok {
+			return NoticeGiftPaidUpgrade{v, shared}
+		}
+	case NoticeTypePrimePaidUpgrade:
+		if v, ok := pick(shared, e.PrimePaidUpgrade, e.SharedChatPrimePaidUpgrade); ok {
+			return NoticePrimePaidUpgrade{v, shared}
+		}
+	case NoticeTypePayItForward:
+		if v, ok := pick(shared, e.PayItForward, e.SharedChatPayItForward); ok {
+			return NoticePayItForward{v, shared}
+		}
+	case NoticeTypeRaid:
+		if v, ok := pick(shared, e.Raid, e.SharedChatRaid); ok {
+			return NoticeRaid{v, shared}
+		}
+	case NoticeTypeUnraid:
+		// Unraid has no fields so twitch may send null for it
+		if !shared {
+			return NoticeUnraid{}
+		}
+	case NoticeTypeAnnouncement:
+		if v, ok := pick(shared, e.Announcement, e.SharedChatAnnouncement); ok {
+			return NoticeAnnouncement{v, shared}
+		}
+	case NoticeTypeBitsBadgeTier:
+		if v, ok := pick(shared, e.BitsBadgeTier, nil); ok {
+			return NoticeBitsBadgeTier{v}
+		}
+	case NoticeTypeCharityDonation:
+		if v, ok := pick(shared, e.CharityDonation, nil); ok {
+			return NoticeCharityDonation{v}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package twitch_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/joeyak/go-twitch-eventsub/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotice(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Json     string
+		Expected twitch.Notice
+	}{
+		{
+			"Resub",
+			`{"notice_type":"resub","resub":{"cumulative_months":10,"sub_tier":"1000"}}`,
+			twitch.NoticeResub{ChatNotificationResub: twitch.ChatNotificationResub{CumulativeMonths: 10, SubTier: "1000"}},
+		},
+		{
+			"SharedChatResub",
+			`{"notice_type":"shared_chat_resub","resub":null,"shared_chat_resub":{"cumulative_months":3}}`,
+			twitch.NoticeResub{ChatNotificationResub: twitch.ChatNotificationResub{CumulativeMonths: 3}, IsSharedChat: true},
+		},
+		{
+			"SharedChatAnnouncement",
+			`{"notice_type":"shared_chat_announcement","shared_chat_announcement":{"color":"BLUE"}}`,
+			twitch.NoticeAnnouncement{ChatNotificationAnnouncement: twitch.ChatNotificationAnnouncement{Color: "BLUE"}, IsSharedChat: true},
+		},
+		{
+			"Unraid",
+			`{"notice_type":"unraid","unraid":null}`,
+			twitch.NoticeUnraid{},
+		},
+		{
+			"CharityDonation",
+			`{"notice_type":"charity_donation","charity_donation":{"charity_name":"example","amount":{"value":500,"decimal_place":2,"currency":"USD"}}}`,
+			twitch.NoticeCharityDonation{ChatNotificationCharityDonation: twitch.ChatNotificationCharityDonation{
+				CharityName: "example",
+				Amount:      twitch.ChatNotificationCharityDonationAmount{Value: 500, DecimalPlace: 2, Currency: "USD"},
+			}},
+		},
+		{"MissingPayload", `{"notice_type":"sub"}`, nil},
+		{"Unknown", `{"notice_type":"something_new"}`, nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			var event twitch.EventChannelChatNotification
+			err := json.Unmarshal([]byte(tc.Json), &event)
+			assert.NoError(t, err)
+
+			notice := event.Notice()
+			assert.Equal(t, tc.Expected, notice)
+			if notice != nil {
+				assert.Equal(t, event.NoticeType, notice.Type())
+				assert.True(t, event.NoticeType.IsValid())
+			}
+		})
+	}
+}
+
+func TestNoticeTypeBase(t *testing.T) {
+	assert.True(t, twitch.NoticeTypeSharedChatSubGift.IsSharedChat())
+	assert.Equal(t, twitch.NoticeTypeSubGift, twitch.NoticeTypeSharedChatSubGift.Base())
+	assert.Equal(t, twitch.NoticeTypeSubGift, twitch.NoticeTypeSubGift.Base())
+
+	assert.True(t, twitch.NoticeTypeSharedChatAnnouncement.IsValid())
+	assert.False(t, twitch.NoticeType("shared_chat_unraid").IsValid())
+	assert.Equal(t, "sub_gift", twitch.NoticeTypeSubGift.String())
+	assert.Contains(t, twitch.NoticeTypes(), twitch.NoticeTypeSharedChatRaid)
+}
@@ -0,0 +1,291 @@
+package twitch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	ErrPoolFull           = fmt.Errorf("pool has no room for more sessions")
+	ErrPoolConnectTimeout = fmt.Errorf("pool member was not welcomed in time")
+)
+
+// Pool spreads subscriptions over several websocket sessions of the same
+// token to get past the per session subscription limit. Sessions are opened
+// when the existing ones are full since twitch closes sessions that don't
+// subscribe to anything.
+type Pool struct {
+	Address         string
+	SubscriptionUrl string
+	Size            int
+
+	// ConnectTimeout bounds how long a new session may take to connect and
+	// get its welcome. Zero waits until the pool's context is done.
+	ConnectTimeout time.Duration
+
+	mu        sync.Mutex
+	growMu    sync.Mutex
+	ctx       context.Context
+	budget    *SubscriptionBudget
+	members   []*Client
+	configure func(client *Client)
+	onError   func(err error)
+}
+
+func NewPool(size int) *Pool {
+	return NewPoolWithUrl(twitchWebsocketUrl, size)
+}
+
+func NewPoolWithUrl(url string, size int) *Pool {
+	if size <= 0 || size > MaxSessionsPerToken {
+		size = MaxSessionsPerToken
+	}
+
+	return &Pool{
+		Address:         url,
+		SubscriptionUrl: subscriptionUrlFor(url),
+		Size:            size,
+		ConnectTimeout:  10 * time.Second,
+		budget:          NewSubscriptionBudget(),
+		onError:         func(err error) { fmt.Printf("ERROR: %v\n", err) },
+	}
+}
+
+// Handlers registers the callbacks of every member. It's called for each
+// client the pool creates, so all sessions share one set of handlers. The
+// OnWelcome callback is wrapped by the pool and still called.
+func (p *Pool) Handlers(configure func(client *Client)) {
+	p.configure = configure
+}
+
+func (p *Pool) OnError(callback func(err error)) {
+	p.onError = callback
+}
+
+func (p *Pool) Budget() *SubscriptionBudget {
+	return p.budget
+}
+
+func (p *Pool) Clients() []*Client {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]*Client(nil), p.members...)
+}
+
+// Connect opens the first session of the pool.
+func (p *Pool) Connect(ctx context.Context) error {
+	p.mu.Lock()
+	p.ctx = ctx
+	p.mu.Unlock()
+
+	_, err := p.grow(0)
+	return err
+}
+
+func (p *Pool) context() context.Context {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.ctx
+}
+
+func (p *Pool) Wait() {
+	for _, client := range p.Clients() {
+		client.Wait()
+	}
+}
+
+func (p *Pool) Close() error {
+	p.mu.Lock()
+	members := p.members
+	p.members = nil
+	p.mu.Unlock()
+
+	var errs []error
+	for _, client := range members {
+		err := client.Close()
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Subscribe subscribes the least loaded session with room for the request,
+// opening a new session when all of them are full.
+func (p *Pool) Subscribe(ctx context.Context, request SubscribeRequest) (SubscribeResponse, error) {
+	for {
+		client := p.pick()
+		if client == nil {
+			var err error
+			client, err = p.grow(len(p.Clients()))
+			if err != nil {
+				return SubscribeResponse{}, fmt.Errorf("could not subscribe to %s: %w", request.Event, err)
+			}
+		}
+
+		request.SessionID = client.SessionID()
+		// the session may have filled up or been dropped since it was picked
+		response, err := client.Subscribe(ctx, request)
+		if errors.Is(err, ErrSessionSubscriptionLimit) || errors.Is(err, ErrSessionClosed) {
+			continue
+		}
+		return response, err
+	}
+}
+
+func (p *Pool) pick() *Client {
+	type load struct {
+		client *Client
+		budget SessionBudget
+	}
+
+	var loads []load
+	for _, client := range p.Clients() {
+		budget := p.budget.Session(client.SessionID())
+		if budget.Remaining > 0 {
+			loads = append(loads, load{client, budget})
+		}
+	}
+
+	if len(loads) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(loads, func(i, j int) bool {
+		if loads[i].budget.Cost != loads[j].budget.Cost {
+			return loads[i].budget.Cost < loads[j].budget.Cost
+		}
+		return loads[i].budget.Subscriptions < loads[j].budget.Subscriptions
+	})
+	return loads[0].client
+}
+
+// grow adds a member unless another caller already grew the pool past the
+// number of members seen by the caller.
+func (p *Pool) grow(seen int) (*Client, error) {
+	p.growMu.Lock()
+	defer p.growMu.Unlock()
+
+	members := p.Clients()
+	if len(members) > seen {
+		return members[len(members)-1], nil
+	}
+	if len(members) >= p.Size {
+		return nil, ErrPoolFull
+	}
+
+	client := NewClientWithUrl(p.Address)
+	client.SubscriptionUrl = p.SubscriptionUrl
+	client.SetBudget(p.budget)
+	client.OnError(p.onError)
+	if p.configure != nil {
+		p.configure(client)
+	}
+
+	welcomed := make(chan struct{})
+	var once sync.Once
+	onWelcome := client.onWelcome
+	client.OnWelcome(func(message WelcomeMessage) {
+		once.Do(func() { close(welcomed) })
+		if onWelcome != nil {
+			onWelcome(message)
+		}
+	})
+
+	// The member's context is canceled if it isn't welcomed in time, which
+	// stops the dial or the read loop of only this member
+	ctx, cancel := context.WithCancel(p.context())
+	var expired atomic.Bool
+	stop := func() {}
+	if p.ConnectTimeout > 0 {
+		timer := time.AfterFunc(p.ConnectTimeout, func() {
+			expired.Store(true)
+			cancel()
+		})
+		stop = func() { timer.Stop() }
+	}
+	fail := func(reason string, err error) (*Client, error) {
+		stop()
+		cancel()
+		if expired.Load() {
+			err = ErrPoolConnectTimeout
+		}
+		return nil, fmt.Errorf("could not %s: %w", reason, err)
+	}
+
+	err := client.ConnectWithContext(ctx, func(ctx context.Context, err error) {
+		p.drop(client, err)
+		cancel()
+	})
+	if err != nil {
+		return fail("connect pool member", err)
+	}
+
+	select {
+	case <-welcomed:
+		stop()
+	case <-ctx.Done():
+	}
+
+	// the timeout can fire right as the welcome arrives
+	if ctx.Err() != nil {
+		client.Close()
+		return fail("wait for pool member welcome", ctx.Err())
+	}
+
+	p.mu.Lock()
+	p.members = append(p.members, client)
+	p.mu.Unlock()
+
+	return client, nil
+}
+
+// drop removes a member whose connection failed and moves its subscriptions
+// to the other sessions once the member's session is released. It's called
+// by the member's read loop after its last message, so revoked subscriptions
+// were already removed from the budget and aren't moved.
+func (p *Pool) drop(client *Client, err error) {
+	sessionID := client.SessionID()
+
+	p.mu.Lock()
+	member := false
+	for i, m := range p.members {
+		if m == client {
+			p.members = append(p.members[:i], p.members[i+1:]...)
+			member = true
+			break
+		}
+	}
+	p.mu.Unlock()
+
+	// members that timed out while growing were never added
+	if !member {
+		return
+	}
+
+	// the session is closed in the budget as its subscriptions are taken,
+	// so a subscription committed after this fails and is retried by
+	// Subscribe instead of being lost
+	subscriptions := p.budget.removeSession(sessionID)
+
+	p.onError(fmt.Errorf("pool session %s dropped: %w", sessionID, err))
+
+	go func() {
+		client.Wait()
+
+		for _, subscription := range subscriptions {
+			request := subscription.Request
+			request.SessionID = ""
+
+			_, err := p.Subscribe(p.context(), request)
+			if err != nil {
+				p.onError(fmt.Errorf("could not rebalance subscription %s: %w", subscription.Subscription.ID, err))
+			}
+		}
+	}()
+}
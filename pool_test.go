@@ -0,0 +1,258 @@
+package twitch_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/google/uuid"
+	"github.com/joeyak/go-twitch-eventsub/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+type poolServer struct {
+	Address string
+	Silent  atomic.Bool
+
+	mu    sync.Mutex
+	conns map[string]*websocket.Conn
+}
+
+func newPoolServer(t *testing.T) *poolServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := &poolServer{
+		Address: listener.Addr().String(),
+		conns:   map[string]*websocket.Conn{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ws", server.handleWebsocket)
+	mux.HandleFunc("/subscriptions", server.handleSubscription)
+	go http.Serve(listener, mux)
+
+	return server
+}
+
+func (s *poolServer) handleWebsocket(w http.ResponseWriter, r *http.Request) {
+	conn, err := websocket.Accept(w, r, nil)
+	if err != nil {
+		panic(err)
+	}
+
+	sessionID := strings.ReplaceAll(uuid.NewString(), "-", "")
+	s.mu.Lock()
+	s.conns[sessionID] = conn
+	s.mu.Unlock()
+
+	if s.Silent.Load() {
+		conn.Read(r.Context())
+		return
+	}
+
+	welcome := twitch.WelcomeMessage{Metadata: newMetadata("session_welcome")}
+	welcome.Payload.Session = twitch.PayloadSession{ID: sessionID, Status: "connected", ConnectedAt: time.Now()}
+	data, _ := json.Marshal(welcome)
+	conn.Write(r.Context(), websocket.MessageText, data)
+
+	conn.Read(r.Context())
+}
+
+func (s *poolServer) handleSubscription(w http.ResponseWriter, r *http.Request) {
+	request, _ := io.ReadAll(r.Body)
+	r.Body.Close()
+
+	var subscription twitch.SubscriptionRequest
+	json.Unmarshal(request, &subscription)
+
+	response, _ := json.Marshal(twitch.SubscribeResponse{
+		Data: []twitch.PayloadSubscription{{
+			SubscriptionRequest: subscription,
+			ID:                  uuid.NewString(),
+			Status:              string(twitch.SubscriptionStatusEnabled),
+		}},
+	})
+	w.WriteHeader(http.StatusAccepted)
+	w.Write(response)
+}
+
+func (s *poolServer) drop(sessionID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.conns[sessionID].Close(websocket.StatusInternalError, "dropped")
+}
+
+func (s *poolServer) revoke(subscription twitch.PayloadSubscription) {
+	var message twitch.RevokeMessage
+	message.Metadata = newMetadata("revocation")
+	message.Payload.Subscription = subscription
+	message.Payload.Subscription.Status = string(twitch.SubscriptionStatusAuthorizationRevoked)
+	data, _ := json.Marshal(message)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.conns[subscription.Transport.SessionID].Write(context.Background(), websocket.MessageText, data)
+}
+
+func TestPool(t *testing.T) {
+	t.Parallel()
+
+	server := newPoolServer(t)
+	pool := twitch.NewPoolWithUrl(fmt.Sprintf("http://%s/ws", server.Address), 2)
+	pool.SubscriptionUrl = fmt.Sprintf("http://%s/subscriptions", server.Address)
+	pool.Budget().MaxSubscriptionsPerSession = 2
+
+	var welcomes atomic.Int32
+	pool.Handlers(func(client *twitch.Client) {
+		client.OnWelcome(func(message twitch.WelcomeMessage) { welcomes.Add(1) })
+	})
+
+	dropped := make(chan struct{})
+	pool.OnError(func(err error) {
+		if strings.Contains(err.Error(), "dropped") {
+			close(dropped)
+			return
+		}
+		t.Errorf("pool error: %v", err)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := pool.Connect(ctx)
+	assert.NoError(t, err)
+
+	for _, event := range []twitch.EventSubscription{twitch.SubChannelFollow, twitch.SubChannelCheer, twitch.SubChannelRaid} {
+		_, err := pool.Subscribe(ctx, twitch.SubscribeRequest{Event: event})
+		assert.NoError(t, err)
+	}
+
+	clients := pool.Clients()
+	assert.Eventually(t, func() bool { return welcomes.Load() == 2 }, time.Second, 10*time.Millisecond)
+	assert.Len(t, clients, 2)
+	assert.Equal(t, 2, pool.Budget().Session(clients[0].SessionID()).Subscriptions)
+	assert.Equal(t, 1, pool.Budget().Session(clients[1].SessionID()).Subscriptions)
+	assert.Equal(t, 3, pool.Budget().Token("", "").Subscriptions)
+
+	server.drop(clients[0].SessionID())
+	<-dropped
+
+	assert.Eventually(t, func() bool {
+		return pool.Budget().Token("", "").Subscriptions == 3
+	}, time.Second, 10*time.Millisecond)
+
+	clients = pool.Clients()
+	assert.Len(t, clients, 2)
+	for _, client := range clients {
+		assert.NotEmpty(t, pool.Budget().Subscriptions(client.SessionID()))
+	}
+
+	assert.NoError(t, pool.Close())
+}
+
+func TestPoolConnectTimeout(t *testing.T) {
+	t.Parallel()
+
+	server := newPoolServer(t)
+	server.Silent.Store(true)
+
+	pool := twitch.NewPoolWithUrl(fmt.Sprintf("http://%s/ws", server.Address), 2)
+	pool.SubscriptionUrl = fmt.Sprintf("http://%s/subscriptions", server.Address)
+	pool.ConnectTimeout = 100 * time.Millisecond
+	pool.Handlers(func(client *twitch.Client) {
+		client.OnWelcome(func(message twitch.WelcomeMessage) {})
+	})
+	pool.OnError(func(err error) { t.Errorf("pool error: %v", err) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	began := time.Now()
+	err := pool.Connect(ctx)
+	assert.ErrorIs(t, err, twitch.ErrPoolConnectTimeout)
+	assert.Less(t, time.Since(began), time.Second)
+	assert.Empty(t, pool.Clients())
+
+	server.Silent.Store(false)
+	err = pool.Connect(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, pool.Clients(), 1)
+
+	assert.NoError(t, pool.Close())
+}
+
+func TestPoolRevoke(t *testing.T) {
+	t.Parallel()
+
+	server := newPoolServer(t)
+	pool := twitch.NewPoolWithUrl(fmt.Sprintf("http://%s/ws", server.Address), 2)
+	pool.SubscriptionUrl = fmt.Sprintf("http://%s/subscriptions", server.Address)
+
+	revoked := make(chan struct{})
+	pool.Handlers(func(client *twitch.Client) {
+		client.OnWelcome(func(message twitch.WelcomeMessage) {})
+		client.OnRevoke(func(message twitch.RevokeMessage) { close(revoked) })
+	})
+
+	dropped := make(chan struct{})
+	pool.OnError(func(err error) {
+		if strings.Contains(err.Error(), "dropped") {
+			close(dropped)
+			return
+		}
+		t.Errorf("pool error: %v", err)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := pool.Connect(ctx)
+	assert.NoError(t, err)
+
+	var subscriptions []twitch.PayloadSubscription
+	for _, event := range []twitch.EventSubscription{twitch.SubChannelFollow, twitch.SubChannelCheer} {
+		response, err := pool.Subscribe(ctx, twitch.SubscribeRequest{Event: event})
+		assert.NoError(t, err)
+		subscriptions = append(subscriptions, response.Data...)
+	}
+	assert.Len(t, subscriptions, 2)
+
+	server.revoke(subscriptions[0])
+	<-revoked
+
+	assert.Eventually(t, func() bool {
+		return pool.Budget().Token("", "").Subscriptions == 1
+	}, time.Second, 10*time.Millisecond)
+
+	server.drop(subscriptions[0].Transport.SessionID)
+	<-dropped
+
+	var rebalanced []twitch.TrackedSubscription
+	assert.Eventually(t, func() bool {
+		for _, client := range pool.Clients() {
+			rebalanced = pool.Budget().Subscriptions(client.SessionID())
+			if len(rebalanced) > 0 {
+				return true
+			}
+		}
+		return false
+	}, time.Second, 10*time.Millisecond)
+
+	assert.Len(t, rebalanced, 1)
+	assert.Equal(t, twitch.SubChannelCheer, rebalanced[0].Request.Event)
+	assert.Equal(t, 1, pool.Budget().Token("", "").Subscriptions)
+
+	assert.NoError(t, pool.Close())
+}
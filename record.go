@@ -0,0 +1,275 @@
+package twitch
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// RedactedValue replaces the values removed by RedactFields.
+const RedactedValue = "redacted"
+
+// RecordedFrame is a websocket message as the client received it.
+type RecordedFrame struct {
+	ReceivedAt time.Time       `json:"received_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// Redactor rewrites a frame before it's recorded, like removing chat
+// messages or user names.
+type Redactor func(data []byte) ([]byte, error)
+
+// Recorder writes the frames received by clients as JSON lines. It can be
+// shared between clients.
+type Recorder struct {
+	mu        sync.Mutex
+	w         io.Writer
+	redactors []Redactor
+}
+
+func NewRecorder(w io.Writer, redactors ...Redactor) *Recorder {
+	return &Recorder{w: w, redactors: redactors}
+}
+
+// Record redacts the frame and writes it.
+func (r *Recorder) Record(receivedAt time.Time, data []byte) error {
+	var err error
+	for _, redact := range r.redactors {
+		data, err = redact(data)
+		if err != nil {
+			return fmt.Errorf("could not redact frame: %w", err)
+		}
+	}
+
+	line, err := json.Marshal(RecordedFrame{ReceivedAt: receivedAt, Data: data})
+	if err != nil {
+		return fmt.Errorf("could not marshal frame: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, err = r.w.Write(append(line, '\n'))
+	if err != nil {
+		return fmt.Errorf("could not write frame: %w", err)
+	}
+	return nil
+}
+
+// SetRecorder records every frame the client receives before it's handled.
+// A nil recorder stops recording.
+func (c *Client) SetRecorder(recorder *Recorder) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.recorder = recorder
+}
+
+func (c *Client) record(receivedAt time.Time, data []byte) {
+	c.mu.Lock()
+	recorder := c.recorder
+	c.mu.Unlock()
+
+	if recorder == nil {
+		return
+	}
+
+	err := recorder.Record(receivedAt, data)
+	if err != nil {
+		c.onError(fmt.Errorf("could not record frame: %w", err))
+	}
+}
+
+// RedactFields replaces the values of the fields with RedactedValue wherever
+// they are in the frame, including objects like a chat message. Null values
+// are kept.
+func RedactFields(fields ...string) Redactor {
+	return redactFields(fields, func(value any) (any, bool) {
+		if value == nil {
+			return nil, true
+		}
+		return RedactedValue, true
+	})
+}
+
+// PseudonymizeFields replaces the string values of the fields with a hash
+// of the value and salt. The same value always gets the same pseudonym, so
+// events of a user can still be matched up in a recording. Other values are
+// kept since they can't be pseudonymized without changing their type.
+func PseudonymizeFields(salt string, fields ...string) Redactor {
+	return redactFields(fields, func(value any) (any, bool) {
+		s, ok := value.(string)
+		if !ok {
+			return value, false
+		}
+		if s == "" {
+			return s, true
+		}
+		sum := sha256.Sum256([]byte(salt + s))
+		return hex.EncodeToString(sum[:8]), true
+	})
+}
+
+// redactFields walks the frame replacing the values of the fields. Values
+// replace doesn't handle are walked into.
+func redactFields(fields []string, replace func(value any) (any, bool)) Redactor {
+	redacted := map[string]bool{}
+	for _, field := range fields {
+		redacted[field] = true
+	}
+
+	var walk func(v any) any
+	walk = func(v any) any {
+		switch v := v.(type) {
+		case map[string]any:
+			for key, value := range v {
+				if redacted[key] {
+					if replaced, ok := replace(value); ok {
+						v[key] = replaced
+						continue
+					}
+				}
+				v[key] = walk(value)
+			}
+		case []any:
+			for i, value := range v {
+				v[i] = walk(value)
+			}
+		}
+		return v
+	}
+
+	return func(data []byte) ([]byte, error) {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+
+		var v any
+		err := decoder.Decode(&v)
+		if err != nil {
+			return nil, fmt.Errorf("could not decode frame: %w", err)
+		}
+		return json.Marshal(walk(v))
+	}
+}
+
+// LoadRecording reads the frames written by a Recorder.
+func LoadRecording(r io.Reader) ([]RecordedFrame, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	var frames []RecordedFrame
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+
+		var frame RecordedFrame
+		err := json.Unmarshal(scanner.Bytes(), &frame)
+		if err != nil {
+			return nil, fmt.Errorf("could not decode frame on line %d: %w", line, err)
+		}
+		frames = append(frames, frame)
+	}
+
+	err := scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("could not read recording: %w", err)
+	}
+	return frames, nil
+}
+
+func LoadRecordingFile(path string) ([]RecordedFrame, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not open recording file: %w", err)
+	}
+	defer file.Close()
+
+	return LoadRecording(file)
+}
+
+// Replayer sends recorded frames to a handler, either all at once with Run
+// or one at a time with Step.
+type Replayer struct {
+	// Speed scales the time between frames, 1 replays in real time and 10
+	// ten times faster. Frames are replayed without waiting when it's 0.
+	Speed float64
+
+	frames  []RecordedFrame
+	handler func(data []byte) error
+	next    int
+}
+
+// NewReplayer replays the frames into the client's callbacks as if they were
+// received by its websocket connection. The client doesn't have to be
+// connected, and a connected client keeps its session and budget since
+// welcomes, reconnects and revocations are only passed to the callbacks.
+func NewReplayer(client *Client, frames []RecordedFrame) *Replayer {
+	return NewReplayerWithHandler(frames, client.replay)
+}
+
+// NewReplayerWithHandler replays the frames into handler, like a mock
+// server sending them to its sessions.
+func NewReplayerWithHandler(frames []RecordedFrame, handler func(data []byte) error) *Replayer {
+	return &Replayer{
+		Speed:   1,
+		frames:  frames,
+		handler: handler,
+	}
+}
+
+// Remaining returns the number of frames not replayed yet.
+func (r *Replayer) Remaining() int {
+	return len(r.frames) - r.next
+}
+
+// Step replays the next frame without waiting, returning io.EOF when every
+// frame was replayed.
+func (r *Replayer) Step() error {
+	if r.next >= len(r.frames) {
+		return io.EOF
+	}
+
+	frame := r.frames[r.next]
+	r.next++
+
+	err := r.handler(frame.Data)
+	if err != nil {
+		return fmt.Errorf("could not replay frame %d: %w", r.next-1, err)
+	}
+	return nil
+}
+
+// Run replays the remaining frames, waiting the time between them scaled
+// by Speed.
+func (r *Replayer) Run(ctx context.Context) error {
+	for r.next < len(r.frames) {
+		if r.next > 0 && r.Speed > 0 {
+			gap := r.frames[r.next].ReceivedAt.Sub(r.frames[r.next-1].ReceivedAt)
+			timer := time.NewTimer(time.Duration(float64(gap) / r.Speed))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			case <-timer.C:
+			}
+		}
+
+		err := r.Step()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Client) replay(data []byte) error {
+	return c.handleMessage(data, true)
+}
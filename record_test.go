@@ -0,0 +1,232 @@
+package twitch_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joeyak/go-twitch-eventsub/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func newFrame(t *testing.T, at time.Time, gen messageDataGenerator) twitch.RecordedFrame {
+	data, _, err := gen()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var compacted bytes.Buffer
+	err = json.Compact(&compacted, data[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return twitch.RecordedFrame{ReceivedAt: at, Data: compacted.Bytes()}
+}
+
+func TestRecorder(t *testing.T) {
+	t.Parallel()
+
+	var buffer bytes.Buffer
+	recorder := twitch.NewRecorder(&buffer, twitch.RedactFields("session_id"))
+
+	assertEventOccurred(t, func(ch chan struct{}) {
+		client := newClient(t, revokeGen)
+		client.SetRecorder(recorder)
+		client.OnRevoke(func(message twitch.RevokeMessage) {
+			close(ch)
+		})
+
+		go connect(t, client)
+	})
+
+	frames, err := twitch.LoadRecording(&buffer)
+	assert.NoError(t, err)
+	if assert.Len(t, frames, 2) {
+		assert.WithinDuration(t, time.Now(), frames[1].ReceivedAt, time.Second)
+		assert.False(t, frames[1].ReceivedAt.Before(frames[0].ReceivedAt))
+
+		var welcome twitch.WelcomeMessage
+		err = json.Unmarshal(frames[0].Data, &welcome)
+		assert.NoError(t, err)
+		assert.Equal(t, "session_welcome", welcome.Metadata.MessageType)
+
+		var message twitch.RevokeMessage
+		err = json.Unmarshal(frames[1].Data, &message)
+		assert.NoError(t, err)
+		assert.Equal(t, twitch.SubscriptionStatusAuthorizationRevoked, message.Payload.Subscription.SubscriptionStatus())
+		assert.Equal(t, twitch.RedactedValue, message.Payload.Subscription.Transport.SessionID)
+	}
+}
+
+func TestRedactors(t *testing.T) {
+	data := []byte(`{"user_id":"1","user_login":"ninja","message":{"text":"hi"},"cost":1,"list":[{"user_login":"ninja"}]}`)
+
+	redacted, err := twitch.RedactFields("text", "user_login")(data)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"user_id":"1","user_login":"redacted","message":{"text":"redacted"},"cost":1,"list":[{"user_login":"redacted"}]}`, string(redacted))
+
+	redacted, err = twitch.RedactFields("message", "cost")(data)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"user_id":"1","user_login":"ninja","message":"redacted","cost":"redacted","list":[{"user_login":"ninja"}]}`, string(redacted))
+
+	pseudonymized, err := twitch.PseudonymizeFields("salt", "user_login")(data)
+	assert.NoError(t, err)
+
+	var v struct {
+		UserLogin string `json:"user_login"`
+		List      []struct {
+			UserLogin string `json:"user_login"`
+		} `json:"list"`
+	}
+	err = json.Unmarshal(pseudonymized, &v)
+	assert.NoError(t, err)
+	assert.NotEqual(t, "ninja", v.UserLogin)
+	assert.Equal(t, v.UserLogin, v.List[0].UserLogin)
+
+	other, err := twitch.PseudonymizeFields("other", "user_login")(data)
+	assert.NoError(t, err)
+	assert.NotEqual(t, string(pseudonymized), string(other))
+
+	_, err = twitch.RedactFields("text")([]byte(`{`))
+	assert.Error(t, err)
+}
+
+func TestLoadRecording(t *testing.T) {
+	recording := `{"received_at":"2024-01-01T12:00:00Z","data":{"metadata":{"message_type":"session_keepalive"}}}
+
+{"received_at":"2024-01-01T12:00:01Z","data":{"metadata":{"message_type":"session_keepalive"}}}
+`
+	frames, err := twitch.LoadRecording(strings.NewReader(recording))
+	assert.NoError(t, err)
+	if assert.Len(t, frames, 2) {
+		assert.Equal(t, time.Second, frames[1].ReceivedAt.Sub(frames[0].ReceivedAt))
+	}
+
+	_, err = twitch.LoadRecording(strings.NewReader("{\n"))
+	assert.Error(t, err)
+}
+
+func TestReplayerStep(t *testing.T) {
+	start := time.Now()
+	frames := []twitch.RecordedFrame{
+		newFrame(t, start, keepAliveGen),
+		newFrame(t, start.Add(time.Hour), getTestEventData(twitch.SubChannelFollow)),
+		newFrame(t, start.Add(2*time.Hour), genReconnectGen("ws://127.0.0.1:1/ws")),
+	}
+
+	keepalives := make(chan struct{}, 1)
+	follows := make(chan twitch.EventChannelFollow, 1)
+	reconnects := make(chan twitch.ReconnectMessage, 1)
+	client := twitch.NewClientWithUrl("ws://127.0.0.1:1/ws")
+	client.OnError(func(err error) { t.Errorf("client registered an error: %v", err) })
+	client.OnKeepAlive(func(message twitch.KeepAliveMessage) { keepalives <- struct{}{} })
+	client.OnEventChannelFollow(func(event twitch.EventChannelFollow, msg twitch.NotificationMessage) { follows <- event })
+	client.OnReconnect(func(message twitch.ReconnectMessage) { reconnects <- message })
+
+	replayer := twitch.NewReplayer(client, frames)
+	assert.Equal(t, 3, replayer.Remaining())
+
+	assert.NoError(t, replayer.Step())
+	select {
+	case <-keepalives:
+	case <-time.After(time.Second):
+		t.Fatal("keepalive was not replayed")
+	}
+
+	assert.NoError(t, replayer.Step())
+	select {
+	case event := <-follows:
+		assert.NotEmpty(t, event.UserLogin)
+	case <-time.After(time.Second):
+		t.Fatal("follow was not replayed")
+	}
+
+	// reconnects are only passed to the callback
+	assert.NoError(t, replayer.Step())
+	select {
+	case <-reconnects:
+	case <-time.After(time.Second):
+		t.Fatal("reconnect was not replayed")
+	}
+	assert.Equal(t, "ws://127.0.0.1:1/ws", client.Address)
+
+	assert.ErrorIs(t, replayer.Step(), io.EOF)
+	assert.Equal(t, 0, replayer.Remaining())
+}
+
+func TestReplayerLiveClient(t *testing.T) {
+	t.Parallel()
+
+	welcomes := make(chan string, 2)
+	client := newClient(t, noDataGen)
+	client.OnWelcome(func(message twitch.WelcomeMessage) { welcomes <- message.Payload.Session.ID })
+
+	err := client.Connect(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	sessionID := <-welcomes
+	assert.Equal(t, sessionID, client.SessionID())
+
+	frames := []twitch.RecordedFrame{
+		{Data: []byte(`{"metadata":{"message_type":"session_welcome"},"payload":{"session":{"id":"replayed"}}}`)},
+	}
+	assert.NoError(t, twitch.NewReplayer(client, frames).Step())
+	select {
+	case id := <-welcomes:
+		assert.Equal(t, "replayed", id)
+	case <-time.After(time.Second):
+		t.Fatal("welcome was not replayed")
+	}
+	assert.Equal(t, sessionID, client.SessionID())
+}
+
+func TestReplayerRun(t *testing.T) {
+	start := time.Now()
+	frames := []twitch.RecordedFrame{
+		newFrame(t, start, keepAliveGen),
+		newFrame(t, start.Add(100*time.Millisecond), keepAliveGen),
+		newFrame(t, start.Add(200*time.Millisecond), keepAliveGen),
+	}
+
+	var handled []time.Time
+	replayer := twitch.NewReplayerWithHandler(frames, func(data []byte) error {
+		handled = append(handled, time.Now())
+		return nil
+	})
+
+	began := time.Now()
+	err := replayer.Run(context.Background())
+	assert.NoError(t, err)
+	if assert.Len(t, handled, 3) {
+		assert.GreaterOrEqual(t, handled[2].Sub(began), 200*time.Millisecond)
+	}
+
+	handled = nil
+	replayer = twitch.NewReplayerWithHandler(frames, func(data []byte) error {
+		handled = append(handled, time.Now())
+		return nil
+	})
+	replayer.Speed = 10
+
+	began = time.Now()
+	err = replayer.Run(context.Background())
+	assert.NoError(t, err)
+	if assert.Len(t, handled, 3) {
+		assert.GreaterOrEqual(t, handled[2].Sub(began), 20*time.Millisecond)
+		assert.Less(t, handled[2].Sub(began), 150*time.Millisecond)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	replayer = twitch.NewReplayerWithHandler(frames, func(data []byte) error { return nil })
+	err = replayer.Run(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 2, replayer.Remaining())
+}
@@ -0,0 +1,471 @@
+package twitch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	maxRedemptionIDs = 50
+	// maxResolvedRedemptions is how many resolved redemption IDs are kept
+	// to drop late or redelivered adds.
+	maxResolvedRedemptions = 1000
+)
+
+type Redemption = EventChannelChannelPointsCustomRewardRedemptionAdd
+
+type Reward = EventChannelChannelPointsCustomRewardAdd
+
+// UpdateRedemptionStatus marks unfulfilled redemptions of a reward as
+// fulfilled or canceled, canceling refunds the channel points. Only the
+// client that created the reward can update its redemptions, and the token
+// needs the channel:manage:redemptions scope.
+func (h *HelixClient) UpdateRedemptionStatus(ctx context.Context, broadcasterID, rewardID string, redemptionIDs []string, status RedemptionStatus) ([]Redemption, error) {
+	if status != RedemptionStatusFulfilled && status != RedemptionStatusCanceled {
+		return nil, fmt.Errorf("could not update redemptions: invalid status %s", status)
+	}
+
+	var redemptions []Redemption
+	for len(redemptionIDs) > 0 {
+		ids := redemptionIDs
+		if len(ids) > maxRedemptionIDs {
+			ids = ids[:maxRedemptionIDs]
+		}
+		redemptionIDs = redemptionIDs[len(ids):]
+
+		query := url.Values{
+			"broadcaster_id": {broadcasterID},
+			"reward_id":      {rewardID},
+			"id":             ids,
+		}
+		body := map[string]string{"status": strings.ToUpper(string(status))}
+
+		var response struct {
+			Data []struct {
+				Redemption
+
+				BroadcasterID    string `json:"broadcaster_id"`
+				BroadcasterLogin string `json:"broadcaster_login"`
+				BroadcasterName  string `json:"broadcaster_name"`
+			} `json:"data"`
+		}
+		err := h.do(ctx, http.MethodPatch, "/channel_points/custom_rewards/redemptions", query, body, &response)
+		if err != nil {
+			return redemptions, fmt.Errorf("could not update redemptions: %w", err)
+		}
+
+		for _, data := range response.Data {
+			redemption := data.Redemption
+			redemption.Broadcaster = Broadcaster{
+				BroadcasterUserId:    data.BroadcasterID,
+				BroadcasterUserLogin: data.BroadcasterLogin,
+				BroadcasterUserName:  data.BroadcasterName,
+			}
+			redemption.Status = RedemptionStatus(strings.ToLower(string(redemption.Status)))
+			redemptions = append(redemptions, redemption)
+		}
+	}
+	return redemptions, nil
+}
+
+// RedemptionMode decides which redemptions a RedemptionQueue handles at the
+// same time.
+type RedemptionMode int
+
+const (
+	// RedemptionPerReward handles one redemption of each reward at a time.
+	RedemptionPerReward RedemptionMode = iota
+	// RedemptionPerUser handles one redemption of each user at a time.
+	RedemptionPerUser
+)
+
+type redemptionHandler struct {
+	ctx     context.Context
+	handler func(ctx context.Context, redemption Redemption) error
+}
+
+// RedemptionQueue keeps the rewards of a channel and their unfulfilled
+// redemptions in the order they were redeemed. Handlers registered with
+// HandleReward take redemptions off the queue one at a time per reward or
+// per user and fulfill them through helix, or cancel them when the handler
+// fails. Redemptions that still couldn't be updated after the retries stay
+// queued and are returned by Failed.
+type RedemptionQueue struct {
+	Mode  RedemptionMode
+	Helix *HelixClient
+	// UpdateRetries is how many times a failed fulfill or cancel of a
+	// handled redemption is retried, waiting UpdateRetryDelay before the
+	// first retry and twice as long before each next one.
+	UpdateRetries    int
+	UpdateRetryDelay time.Duration
+
+	notifier
+
+	rewards      map[string]Reward
+	pending      []Redemption
+	resolved     map[string]RedemptionStatus
+	resolvedIDs  []string
+	handlers     map[string]redemptionHandler
+	busy         map[string]bool
+	running      map[string]bool
+	failed       map[string]RedemptionStatus
+	onTransition func(redemption Redemption, from RedemptionStatus)
+	onError      func(err error)
+}
+
+func NewRedemptionQueue(helix *HelixClient) *RedemptionQueue {
+	return &RedemptionQueue{
+		Helix:            helix,
+		UpdateRetries:    3,
+		UpdateRetryDelay: time.Second,
+		rewards:          map[string]Reward{},
+		resolved:         map[string]RedemptionStatus{},
+		handlers:         map[string]redemptionHandler{},
+		busy:             map[string]bool{},
+		running:          map[string]bool{},
+		failed:           map[string]RedemptionStatus{},
+		onError:          func(err error) { fmt.Printf("ERROR: %v\n", err) },
+	}
+}
+
+// OnTransition is called when a redemption is queued, with an empty from
+// status, and when it's fulfilled or canceled.
+func (q *RedemptionQueue) OnTransition(callback func(redemption Redemption, from RedemptionStatus)) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.onTransition = callback
+}
+
+func (q *RedemptionQueue) OnError(callback func(err error)) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.onError = callback
+}
+
+// HandleReward registers the handler of a reward. The redemption is
+// fulfilled when the handler returns nil and canceled otherwise. The
+// context is passed to the handler and used for the helix calls.
+func (q *RedemptionQueue) HandleReward(ctx context.Context, rewardID string, handler func(ctx context.Context, redemption Redemption) error) {
+	q.mu.Lock()
+	q.handlers[rewardID] = redemptionHandler{ctx, handler}
+	q.mu.Unlock()
+
+	q.dispatch()
+}
+
+func (q *RedemptionQueue) Reward(id string) (Reward, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	reward, ok := q.rewards[id]
+	return reward, ok
+}
+
+func (q *RedemptionQueue) Rewards() []Reward {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	rewards := make([]Reward, 0, len(q.rewards))
+	for _, reward := range q.rewards {
+		rewards = append(rewards, reward)
+	}
+	sort.Slice(rewards, func(i, j int) bool { return rewards[i].Title < rewards[j].Title })
+	return rewards
+}
+
+// Pending returns the unfulfilled redemptions of the reward, oldest first.
+// An empty reward ID returns the redemptions of every reward.
+func (q *RedemptionQueue) Pending(rewardID string) []Redemption {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var pending []Redemption
+	for _, redemption := range q.pending {
+		if rewardID == "" || redemption.Reward.ID == rewardID {
+			pending = append(pending, redemption)
+		}
+	}
+	return pending
+}
+
+// Next returns the oldest unfulfilled redemption of the reward.
+func (q *RedemptionQueue) Next(rewardID string) (Redemption, bool) {
+	pending := q.Pending(rewardID)
+	if len(pending) == 0 {
+		return Redemption{}, false
+	}
+	return pending[0], true
+}
+
+// Failed returns the handled redemptions that couldn't be fulfilled or
+// canceled, oldest first. They stay unfulfilled on twitch until they're
+// updated with RetryFailed, Fulfill or Cancel.
+func (q *RedemptionQueue) Failed() []Redemption {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var failed []Redemption
+	for _, redemption := range q.pending {
+		if _, ok := q.failed[redemption.ID]; ok {
+			failed = append(failed, redemption)
+		}
+	}
+	return failed
+}
+
+// RetryFailed updates the failed redemptions to the status their handler
+// decided on.
+func (q *RedemptionQueue) RetryFailed(ctx context.Context) error {
+	q.mu.Lock()
+	failed := map[string]RedemptionStatus{}
+	var redemptions []Redemption
+	for _, redemption := range q.pending {
+		if status, ok := q.failed[redemption.ID]; ok {
+			failed[redemption.ID] = status
+			redemptions = append(redemptions, redemption)
+		}
+	}
+	q.mu.Unlock()
+
+	var errs []error
+	for _, redemption := range redemptions {
+		err := q.update(ctx, redemption, failed[redemption.ID])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("could not update redemption %s: %w", redemption.ID, err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("could not update %d of %d failed redemptions: %w", len(errs), len(redemptions), errs[0])
+	}
+	return nil
+}
+
+func (q *RedemptionQueue) Fulfill(ctx context.Context, redemption Redemption) error {
+	return q.update(ctx, redemption, RedemptionStatusFulfilled)
+}
+
+// Cancel cancels the redemption, which refunds the channel points.
+func (q *RedemptionQueue) Cancel(ctx context.Context, redemption Redemption) error {
+	return q.update(ctx, redemption, RedemptionStatusCanceled)
+}
+
+func (q *RedemptionQueue) update(ctx context.Context, redemption Redemption, status RedemptionStatus) error {
+	if q.Helix == nil {
+		return fmt.Errorf("could not update redemption %s: %w", redemption.ID, ErrNoHelixClient)
+	}
+
+	_, err := q.Helix.UpdateRedemptionStatus(ctx, redemption.BroadcasterUserId, redemption.Reward.ID, []string{redemption.ID}, status)
+	if err != nil {
+		return err
+	}
+
+	redemption.Status = status
+	q.resolve(redemption)
+	return nil
+}
+
+func (q *RedemptionQueue) HandleRewardAdd(event EventChannelChannelPointsCustomRewardAdd, msg NotificationMessage) {
+	q.mu.Lock()
+	q.rewards[event.ID] = event
+	q.mu.Unlock()
+}
+
+func (q *RedemptionQueue) HandleRewardUpdate(event EventChannelChannelPointsCustomRewardUpdate, msg NotificationMessage) {
+	q.HandleRewardAdd(EventChannelChannelPointsCustomRewardAdd(event), msg)
+}
+
+// HandleRewardRemove forgets the reward and its queued redemptions.
+func (q *RedemptionQueue) HandleRewardRemove(event EventChannelChannelPointsCustomRewardRemove, msg NotificationMessage) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	delete(q.rewards, event.ID)
+	delete(q.handlers, event.ID)
+
+	pending := q.pending[:0:0]
+	for _, redemption := range q.pending {
+		if redemption.Reward.ID != event.ID {
+			pending = append(pending, redemption)
+		} else {
+			delete(q.failed, redemption.ID)
+		}
+	}
+	q.pending = pending
+}
+
+func (q *RedemptionQueue) HandleRedemptionAdd(event EventChannelChannelPointsCustomRewardRedemptionAdd, msg NotificationMessage) {
+	if event.Status != RedemptionStatusUnfulfilled && event.Status != "" {
+		q.resolve(event)
+		return
+	}
+
+	q.mu.Lock()
+	if _, ok := q.resolved[event.ID]; ok || q.isPending(event.ID) {
+		q.mu.Unlock()
+		return
+	}
+
+	if _, ok := q.rewards[event.Reward.ID]; !ok {
+		q.rewards[event.Reward.ID] = Reward{
+			Broadcaster: event.Broadcaster,
+			ID:          event.Reward.ID,
+			Title:       event.Reward.Title,
+			Cost:        event.Reward.Cost,
+			Prompt:      event.Reward.Prompt,
+		}
+	}
+
+	event.Status = RedemptionStatusUnfulfilled
+	q.pending = append(q.pending, event)
+	sort.SliceStable(q.pending, func(i, j int) bool { return q.pending[i].RedeemedAt.Before(q.pending[j].RedeemedAt) })
+
+	q.notify(event, "")
+	q.dispatch()
+}
+
+func (q *RedemptionQueue) HandleRedemptionUpdate(event EventChannelChannelPointsCustomRewardRedemptionUpdate, msg NotificationMessage) {
+	q.resolve(Redemption(event))
+}
+
+// resolve moves the redemption out of the queue. Redemptions resolved
+// before they were queued are remembered so the late add is dropped.
+func (q *RedemptionQueue) resolve(redemption Redemption) {
+	q.mu.Lock()
+	if _, ok := q.resolved[redemption.ID]; ok {
+		q.mu.Unlock()
+		return
+	}
+	q.resolved[redemption.ID] = redemption.Status
+	q.resolvedIDs = append(q.resolvedIDs, redemption.ID)
+	if len(q.resolvedIDs) > maxResolvedRedemptions {
+		delete(q.resolved, q.resolvedIDs[0])
+		q.resolvedIDs = q.resolvedIDs[1:]
+	}
+	delete(q.running, redemption.ID)
+	delete(q.failed, redemption.ID)
+
+	from := RedemptionStatus("")
+	for i, pending := range q.pending {
+		if pending.ID == redemption.ID {
+			from = pending.Status
+			q.pending = append(q.pending[:i:i], q.pending[i+1:]...)
+			break
+		}
+	}
+
+	q.notify(redemption, from)
+}
+
+// fail keeps a redemption that couldn't be fulfilled or canceled queued
+// without handling it again, since twitch doesn't redeliver it.
+func (q *RedemptionQueue) fail(redemption Redemption, status RedemptionStatus) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	delete(q.running, redemption.ID)
+	if q.isPending(redemption.ID) {
+		q.failed[redemption.ID] = status
+	}
+}
+
+func (q *RedemptionQueue) isPending(id string) bool {
+	for _, redemption := range q.pending {
+		if redemption.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (q *RedemptionQueue) key(redemption Redemption) string {
+	if q.Mode == RedemptionPerUser {
+		return redemption.UserID
+	}
+	return redemption.Reward.ID
+}
+
+// dispatch starts the handlers of the oldest redemptions whose reward or
+// user is not already being handled.
+func (q *RedemptionQueue) dispatch() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for _, redemption := range q.pending {
+		handler, ok := q.handlers[redemption.Reward.ID]
+		key := q.key(redemption)
+		_, failed := q.failed[redemption.ID]
+		if !ok || failed || q.busy[key] || q.running[redemption.ID] {
+			continue
+		}
+
+		q.busy[key] = true
+		q.running[redemption.ID] = true
+		go q.run(handler, key, redemption)
+	}
+}
+
+func (q *RedemptionQueue) run(handler redemptionHandler, key string, redemption Redemption) {
+	defer func() {
+		q.mu.Lock()
+		delete(q.busy, key)
+		q.mu.Unlock()
+
+		q.dispatch()
+	}()
+
+	status := RedemptionStatusFulfilled
+	err := handler.handler(handler.ctx, redemption)
+	if err != nil {
+		q.error(fmt.Errorf("could not handle redemption %s: %w", redemption.ID, err))
+		status = RedemptionStatusCanceled
+	}
+
+	err = q.updateWithRetries(handler.ctx, redemption, status)
+	if err != nil {
+		q.fail(redemption, status)
+		q.error(fmt.Errorf("could not update redemption %s to %s: %w", redemption.ID, status, err))
+	}
+}
+
+func (q *RedemptionQueue) updateWithRetries(ctx context.Context, redemption Redemption, status RedemptionStatus) error {
+	delay := q.UpdateRetryDelay
+	for retry := 0; ; retry++ {
+		err := q.update(ctx, redemption, status)
+		if err == nil || retry >= q.UpdateRetries || errors.Is(err, ErrNoHelixClient) {
+			return err
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		delay *= 2
+	}
+}
+
+func (q *RedemptionQueue) error(err error) {
+	q.mu.Lock()
+	onError := q.onError
+	q.mu.Unlock()
+
+	if onError != nil {
+		onError(err)
+	}
+}
+
+func (q *RedemptionQueue) notify(redemption Redemption, from RedemptionStatus) {
+	onTransition := q.onTransition
+	q.unlockAndNotify(func() {
+		if onTransition != nil {
+			onTransition(redemption, from)
+		}
+	})
+}
@@ -0,0 +1,236 @@
+package twitch_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/joeyak/go-twitch-eventsub/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+type redemptionUpdate struct {
+	ID     string
+	Status string
+}
+
+func newRedemptionServer(t *testing.T) (*twitch.HelixClient, func() []redemptionUpdate) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var mu sync.Mutex
+	var updates []redemptionUpdate
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/channel_points/custom_rewards/redemptions", func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		var body struct {
+			Status string `json:"status"`
+		}
+		json.Unmarshal(data, &body)
+
+		mu.Lock()
+		updates = append(updates, redemptionUpdate{r.URL.Query().Get("id"), body.Status})
+		mu.Unlock()
+
+		fmt.Fprintf(w, `{"data":[{"id":%q,"broadcaster_id":%q,"status":%q}]}`, r.URL.Query().Get("id"), r.URL.Query().Get("broadcaster_id"), body.Status)
+	})
+	go http.Serve(listener, mux)
+
+	helix := twitch.NewHelixClientWithUrl(fmt.Sprintf("http://%s", listener.Addr()), "client", "token")
+	helix.RateLimiter = newTestRateLimiter()
+	return helix, func() []redemptionUpdate {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]redemptionUpdate(nil), updates...)
+	}
+}
+
+func newRedemption(id, userID string, redeemedAt time.Time) twitch.EventChannelChannelPointsCustomRewardRedemptionAdd {
+	redemption := twitch.EventChannelChannelPointsCustomRewardRedemptionAdd{
+		ID:         id,
+		Status:     twitch.RedemptionStatusUnfulfilled,
+		Reward:     twitch.CustomChannelPointReward{ID: "reward", Title: "Hydrate"},
+		RedeemedAt: redeemedAt,
+	}
+	redemption.BroadcasterUserId = "1337"
+	redemption.UserID = userID
+	return redemption
+}
+
+func TestUpdateRedemptionStatus(t *testing.T) {
+	t.Parallel()
+
+	helix, updates := newRedemptionServer(t)
+	redemptions, err := helix.UpdateRedemptionStatus(context.Background(), "1337", "reward", []string{"a"}, twitch.RedemptionStatusCanceled)
+	assert.NoError(t, err)
+	assert.Equal(t, []redemptionUpdate{{"a", "CANCELED"}}, updates())
+	assert.Len(t, redemptions, 1)
+	assert.Equal(t, twitch.RedemptionStatusCanceled, redemptions[0].Status)
+	assert.Equal(t, "1337", redemptions[0].BroadcasterUserId)
+
+	_, err = helix.UpdateRedemptionStatus(context.Background(), "1337", "reward", []string{"a"}, twitch.RedemptionStatusUnfulfilled)
+	assert.Error(t, err)
+}
+
+func TestRedemptionQueue(t *testing.T) {
+	t.Parallel()
+
+	helix, updates := newRedemptionServer(t)
+	queue := twitch.NewRedemptionQueue(helix)
+	queue.OnError(func(err error) {})
+
+	now := time.Now()
+	msg := newNotificationAt(now)
+	queue.HandleRedemptionAdd(newRedemption("second", "kappa", now.Add(time.Second)), msg)
+	queue.HandleRedemptionAdd(newRedemption("first", "pogchamp", now), msg)
+	queue.HandleRedemptionAdd(newRedemption("first", "pogchamp", now), msg)
+
+	pending := queue.Pending("reward")
+	assert.Len(t, pending, 2)
+	assert.Equal(t, "first", pending[0].ID)
+
+	reward, ok := queue.Reward("reward")
+	assert.True(t, ok)
+	assert.Equal(t, "Hydrate", reward.Title)
+
+	var mu sync.Mutex
+	var handled []string
+	queue.HandleReward(context.Background(), "reward", func(ctx context.Context, redemption twitch.Redemption) error {
+		mu.Lock()
+		handled = append(handled, redemption.ID)
+		mu.Unlock()
+
+		if redemption.ID == "second" {
+			return fmt.Errorf("out of water")
+		}
+		return nil
+	})
+
+	assert.Eventually(t, func() bool { return len(queue.Pending("")) == 0 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"first", "second"}, handled)
+	assert.Equal(t, []redemptionUpdate{{"first", "FULFILLED"}, {"second", "CANCELED"}}, updates())
+}
+
+func TestRedemptionQueueLateAdd(t *testing.T) {
+	t.Parallel()
+
+	queue := twitch.NewRedemptionQueue(nil)
+
+	var transitions []twitch.RedemptionStatus
+	queue.OnTransition(func(redemption twitch.Redemption, from twitch.RedemptionStatus) {
+		transitions = append(transitions, from, redemption.Status)
+	})
+
+	now := time.Now()
+	update := newRedemption("late", "kappa", now)
+	update.Status = twitch.RedemptionStatusFulfilled
+	queue.HandleRedemptionUpdate(twitch.EventChannelChannelPointsCustomRewardRedemptionUpdate(update), newNotificationAt(now))
+	queue.HandleRedemptionAdd(newRedemption("late", "kappa", now), newNotificationAt(now))
+	assert.Empty(t, queue.Pending(""))
+
+	queue.HandleRedemptionAdd(newRedemption("queued", "kappa", now), newNotificationAt(now))
+	err := queue.Fulfill(context.Background(), queue.Pending("")[0])
+	assert.ErrorIs(t, err, twitch.ErrNoHelixClient)
+
+	assert.Equal(t, []twitch.RedemptionStatus{"", twitch.RedemptionStatusFulfilled, "", twitch.RedemptionStatusUnfulfilled}, transitions)
+}
+
+func TestRedemptionQueueFailedUpdate(t *testing.T) {
+	t.Parallel()
+
+	queue := twitch.NewRedemptionQueue(nil)
+	errs := make(chan error, 1)
+	queue.OnError(func(err error) { errs <- err })
+
+	now := time.Now()
+	queue.HandleRedemptionAdd(newRedemption("a", "kappa", now), newNotificationAt(now))
+	var calls atomic.Int32
+	queue.HandleReward(context.Background(), "reward", func(ctx context.Context, redemption twitch.Redemption) error {
+		calls.Add(1)
+		return nil
+	})
+
+	select {
+	case err := <-errs:
+		assert.ErrorIs(t, err, twitch.ErrNoHelixClient)
+	case <-time.After(time.Second):
+		t.Fatal("failed fulfill was not reported")
+	}
+
+	// the redemption stays queued without being handled again
+	assert.Eventually(t, func() bool { return len(queue.Failed()) == 1 }, time.Second, 10*time.Millisecond)
+	queue.HandleRedemptionAdd(newRedemption("a", "kappa", now), newNotificationAt(now))
+	assert.Len(t, queue.Pending(""), 1)
+	assert.Equal(t, int32(1), calls.Load())
+
+	helix, updates := newRedemptionServer(t)
+	queue.Helix = helix
+	assert.NoError(t, queue.RetryFailed(context.Background()))
+	assert.Empty(t, queue.Pending(""))
+	assert.Empty(t, queue.Failed())
+	assert.Equal(t, []redemptionUpdate{{"a", "FULFILLED"}}, updates())
+}
+
+func TestRedemptionQueueUpdateRetries(t *testing.T) {
+	t.Parallel()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var requests atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/channel_points/custom_rewards/redemptions", func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) <= 2 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprintf(w, `{"data":[{"id":%q,"status":"FULFILLED"}]}`, r.URL.Query().Get("id"))
+	})
+	go http.Serve(listener, mux)
+
+	helix := twitch.NewHelixClientWithUrl(fmt.Sprintf("http://%s", listener.Addr()), "client", "token")
+	helix.RateLimiter = newTestRateLimiter()
+
+	queue := twitch.NewRedemptionQueue(helix)
+	queue.UpdateRetryDelay = time.Millisecond
+	queue.OnError(func(err error) { t.Errorf("queue error: %v", err) })
+
+	now := time.Now()
+	queue.HandleRedemptionAdd(newRedemption("a", "kappa", now), newNotificationAt(now))
+	queue.HandleReward(context.Background(), "reward", func(ctx context.Context, redemption twitch.Redemption) error { return nil })
+
+	assert.Eventually(t, func() bool { return len(queue.Pending("")) == 0 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, int32(3), requests.Load())
+	assert.Empty(t, queue.Failed())
+}
+
+func TestRedemptionQueueResolvedLimit(t *testing.T) {
+	t.Parallel()
+
+	queue := twitch.NewRedemptionQueue(nil)
+	now := time.Now()
+
+	for i := 0; i <= 1000; i++ {
+		update := newRedemption(fmt.Sprint(i), "kappa", now)
+		update.Status = twitch.RedemptionStatusFulfilled
+		queue.HandleRedemptionUpdate(twitch.EventChannelChannelPointsCustomRewardRedemptionUpdate(update), newNotificationAt(now))
+	}
+
+	// only the most recent resolved redemptions are remembered
+	queue.HandleRedemptionAdd(newRedemption("1000", "kappa", now), newNotificationAt(now))
+	assert.Empty(t, queue.Pending(""))
+	queue.HandleRedemptionAdd(newRedemption("0", "kappa", now), newNotificationAt(now))
+	assert.Len(t, queue.Pending(""), 1)
+}
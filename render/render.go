@@ -0,0 +1,233 @@
+package render
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/joeyak/go-twitch-eventsub/v3"
+)
+
+const (
+	DefaultEmoteUrlTemplate     = "https://static-cdn.jtvnw.net/emoticons/v2/{{id}}/{{format}}/{{theme_mode}}/{{scale}}"
+	DefaultCheermoteUrlTemplate = "https://d3aqoihi2n8ty8.cloudfront.net/actions/{{prefix}}/{{theme_mode}}/{{format}}/{{tier}}/{{scale}}.{{extension}}"
+)
+
+type Theme string
+
+const (
+	ThemeDark  Theme = "dark"
+	ThemeLight Theme = "light"
+)
+
+type Scale string
+
+const (
+	Scale1x Scale = "1.0"
+	Scale2x Scale = "2.0"
+	Scale3x Scale = "3.0"
+)
+
+type Options struct {
+	Theme    Theme
+	Scale    Scale
+	Animated bool
+
+	EmoteUrlTemplate     string
+	CheermoteUrlTemplate string
+}
+
+func DefaultOptions() Options {
+	return Options{
+		Theme:                ThemeDark,
+		Scale:                Scale1x,
+		Animated:             true,
+		EmoteUrlTemplate:     DefaultEmoteUrlTemplate,
+		CheermoteUrlTemplate: DefaultCheermoteUrlTemplate,
+	}
+}
+
+func (o Options) withDefaults() Options {
+	defaults := DefaultOptions()
+	if o.Theme == "" {
+		o.Theme = defaults.Theme
+	}
+	if o.Scale == "" {
+		o.Scale = defaults.Scale
+	}
+	if o.EmoteUrlTemplate == "" {
+		o.EmoteUrlTemplate = defaults.EmoteUrlTemplate
+	}
+	if o.CheermoteUrlTemplate == "" {
+		o.CheermoteUrlTemplate = defaults.CheermoteUrlTemplate
+	}
+	return o
+}
+
+func EmoteUrl(emote twitch.ChatMessageFragmentEmote, opts Options) string {
+	opts = opts.withDefaults()
+
+	format := "static"
+	if opts.Animated {
+		for _, f := range emote.Format {
+			if f == "animated" {
+				format = f
+			}
+		}
+	}
+
+	return strings.NewReplacer(
+		"{{id}}", emote.Id,
+		"{{format}}", format,
+		"{{theme_mode}}", string(opts.Theme),
+		"{{scale}}", string(opts.Scale),
+	).Replace(opts.EmoteUrlTemplate)
+}
+
+func CheermoteUrl(cheermote twitch.ChatMessageFragmentCheermote, opts Options) string {
+	opts = opts.withDefaults()
+
+	format, extension := "static", "png"
+	if opts.Animated {
+		format, extension = "animated", "gif"
+	}
+
+	// Cheermote images use whole number scales
+	scale := strings.TrimSuffix(string(opts.Scale), ".0")
+
+	return strings.NewReplacer(
+		"{{prefix}}", strings.ToLower(cheermote.Prefix),
+		"{{theme_mode}}", string(opts.Theme),
+		"{{format}}", format,
+		"{{tier}}", strconv.Itoa(cheermote.Tier),
+		"{{scale}}", scale,
+		"{{extension}}", extension,
+	).Replace(opts.CheermoteUrlTemplate)
+}
+
+func fragments(message twitch.ChatMessage) []twitch.ChatMessageFragment {
+	if len(message.Fragments) == 0 && message.Text != "" {
+		return []twitch.ChatMessageFragment{{Type: "text", Text: message.Text}}
+	}
+	return message.Fragments
+}
+
+// HTML renders the message with escaped text and images for emotes and
+// cheermotes.
+func HTML(message twitch.ChatMessage, opts Options) string {
+	var sb strings.Builder
+	for _, fragment := range fragments(message) {
+		text := html.EscapeString(fragment.Text)
+
+		switch {
+		case fragment.Type == "emote" && fragment.Emote != nil:
+			fmt.Fprintf(&sb, `<img class="emote" src="%s" alt="%s" title="%s">`, html.EscapeString(EmoteUrl(*fragment.Emote, opts)), text, text)
+		case fragment.Type == "cheermote" && fragment.Cheermote != nil:
+			fmt.Fprintf(&sb, `<img class="cheermote" src="%s" alt="%s" title="%s"><span class="cheermote-bits">%d</span>`,
+				html.EscapeString(CheermoteUrl(*fragment.Cheermote, opts)), text, text, fragment.Cheermote.Bits)
+		case fragment.Type == "mention":
+			fmt.Fprintf(&sb, `<span class="mention">%s</span>`, text)
+		default:
+			sb.WriteString(text)
+		}
+	}
+	return sb.String()
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`", "~", `\~`,
+	"[", `\[`, "]", `\]`, "(", `\(`, ")", `\)`, "#", `\#`,
+	"<", `\<`, ">", `\>`, "|", `\|`, "!", `\!`,
+)
+
+func Markdown(message twitch.ChatMessage, opts Options) string {
+	var sb strings.Builder
+	for _, fragment := range fragments(message) {
+		text := markdownEscaper.Replace(fragment.Text)
+
+		switch {
+		case fragment.Type == "emote" && fragment.Emote != nil:
+			fmt.Fprintf(&sb, "![%s](%s)", text, EmoteUrl(*fragment.Emote, opts))
+		case fragment.Type == "cheermote" && fragment.Cheermote != nil:
+			fmt.Fprintf(&sb, "![%s](%s)", text, CheermoteUrl(*fragment.Cheermote, opts))
+		case fragment.Type == "mention":
+			fmt.Fprintf(&sb, "**%s**", text)
+		default:
+			sb.WriteString(text)
+		}
+	}
+	return sb.String()
+}
+
+// Plain renders the message text with emotes stripped.
+func Plain(message twitch.ChatMessage) string {
+	var sb strings.Builder
+	for _, fragment := range fragments(message) {
+		if fragment.Type == "emote" {
+			continue
+		}
+		sb.WriteString(fragment.Text)
+	}
+	return strings.Join(strings.Fields(sb.String()), " ")
+}
+
+const (
+	ansiReset = "\x1b[0m"
+	ansiBold  = "\x1b[1m"
+)
+
+// ansiSanitize removes control characters, including the C1 controls like
+// U+009B, so chat text can't send its own escape sequences or move the
+// cursor.
+func ansiSanitize(text string) string {
+	return strings.Map(func(r rune) rune {
+		if r != '\t' && unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, text)
+}
+
+func ansiColor(color string) string {
+	color = strings.TrimPrefix(color, "#")
+	if len(color) != 6 {
+		return ""
+	}
+
+	rgb, err := strconv.ParseUint(color, 16, 32)
+	if err != nil {
+		return ""
+	}
+
+	return fmt.Sprintf("\x1b[38;2;%d;%d;%dm", rgb>>16&0xff, rgb>>8&0xff, rgb&0xff)
+}
+
+// ANSI renders a chat line for terminals with the chatter's name in their
+// color, mentions in bold and emotes and cheermotes in their own colors.
+func ANSI(event twitch.EventChannelChatMessage) string {
+	var sb strings.Builder
+
+	color := ansiColor(event.Color)
+	if color != "" {
+		sb.WriteString(color)
+	}
+	sb.WriteString(ansiBold + ansiSanitize(event.ChatterUserName) + ansiReset + ": ")
+
+	for _, fragment := range fragments(event.Message) {
+		text := ansiSanitize(fragment.Text)
+
+		switch fragment.Type {
+		case "emote":
+			sb.WriteString("\x1b[35m" + text + ansiReset)
+		case "cheermote":
+			sb.WriteString("\x1b[33m" + text + ansiReset)
+		case "mention":
+			sb.WriteString(ansiBold + text + ansiReset)
+		default:
+			sb.WriteString(text)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,76 @@
+package render_test
+
+import (
+	"testing"
+
+	"github.com/joeyak/go-twitch-eventsub/v3"
+	"github.com/joeyak/go-twitch-eventsub/v3/render"
+	"github.com/stretchr/testify/assert"
+)
+
+var testMessage = twitch.ChatMessage{
+	Text: "Hi <b>@streamer</b> Kappa Cheer100",
+	Fragments: []twitch.ChatMessageFragment{
+		{Type: "text", Text: "Hi <b>"},
+		{Type: "mention", Text: "@streamer", Mention: &twitch.ChatMessageFragmentMention{UserID: "1", UserLogin: "streamer", UserName: "streamer"}},
+		{Type: "text", Text: "</b> "},
+		{Type: "emote", Text: "Kappa", Emote: &twitch.ChatMessageFragmentEmote{Id: "25", Format: []string{"static", "animated"}}},
+		{Type: "text", Text: " "},
+		{Type: "cheermote", Text: "Cheer100", Cheermote: &twitch.ChatMessageFragmentCheermote{Prefix: "Cheer", Bits: 100, Tier: 100}},
+	},
+}
+
+func TestEmoteUrl(t *testing.T) {
+	emote := *testMessage.Fragments[3].Emote
+
+	assert.Equal(t, "https://static-cdn.jtvnw.net/emoticons/v2/25/animated/dark/1.0", render.EmoteUrl(emote, render.DefaultOptions()))
+	assert.Equal(t, "https://static-cdn.jtvnw.net/emoticons/v2/25/static/light/3.0", render.EmoteUrl(emote, render.Options{Theme: render.ThemeLight, Scale: render.Scale3x}))
+}
+
+func TestCheermoteUrl(t *testing.T) {
+	cheermote := *testMessage.Fragments[5].Cheermote
+
+	assert.Equal(t, "https://d3aqoihi2n8ty8.cloudfront.net/actions/cheer/dark/animated/100/1.gif", render.CheermoteUrl(cheermote, render.DefaultOptions()))
+	assert.Equal(t, "https://d3aqoihi2n8ty8.cloudfront.net/actions/cheer/dark/static/100/2.png", render.CheermoteUrl(cheermote, render.Options{Scale: render.Scale2x}))
+}
+
+func TestHTML(t *testing.T) {
+	expected := `Hi &lt;b&gt;<span class="mention">@streamer</span>&lt;/b&gt; ` +
+		`<img class="emote" src="https://static-cdn.jtvnw.net/emoticons/v2/25/static/dark/1.0" alt="Kappa" title="Kappa"> ` +
+		`<img class="cheermote" src="https://d3aqoihi2n8ty8.cloudfront.net/actions/cheer/dark/static/100/1.png" alt="Cheer100" title="Cheer100"><span class="cheermote-bits">100</span>`
+
+	assert.Equal(t, expected, render.HTML(testMessage, render.Options{}))
+}
+
+func TestMarkdown(t *testing.T) {
+	expected := `Hi \<b\>**@streamer**\</b\> ` +
+		`![Kappa](https://static-cdn.jtvnw.net/emoticons/v2/25/static/dark/1.0) ` +
+		`![Cheer100](https://d3aqoihi2n8ty8.cloudfront.net/actions/cheer/dark/static/100/1.png)`
+
+	assert.Equal(t, expected, render.Markdown(testMessage, render.Options{}))
+}
+
+func TestPlain(t *testing.T) {
+	assert.Equal(t, "Hi <b>@streamer</b> Cheer100", render.Plain(testMessage))
+	assert.Equal(t, "no fragments", render.Plain(twitch.ChatMessage{Text: "no fragments"}))
+}
+
+func TestANSI(t *testing.T) {
+	event := twitch.EventChannelChatMessage{
+		Chatter: twitch.Chatter{ChatterUserName: "viewer\x1b[2J"},
+		Color:   "#FF8000",
+		Message: twitch.ChatMessage{Text: "hello"},
+	}
+
+	assert.Equal(t, "\x1b[38;2;255;128;0m\x1b[1mviewer[2J\x1b[0m: hello", render.ANSI(event))
+
+	event.Color = ""
+	assert.Equal(t, "\x1b[1mviewer[2J\x1b[0m: hello", render.ANSI(event))
+
+	// C1 controls and carriage returns are removed too
+	event.ChatterUserName = "viewer\u009b2J\r"
+	event.Message = twitch.ChatMessage{Fragments: []twitch.ChatMessageFragment{
+		{Type: "text", Text: "hi\u009d0;title\u0007\rthere\tfriend"},
+	}}
+	assert.Equal(t, "\x1b[1mviewer2J\x1b[0m: hi0;titlethere\tfriend", render.ANSI(event))
+}
@@ -0,0 +1,211 @@
+package twitch
+
+import (
+	"time"
+)
+
+const DefaultSharedChatDedupWindow = 10 * time.Second
+
+// SharedChatOrigin tells where a chat, notification or moderate event came
+// from.
+type SharedChatOrigin struct {
+	// Source is the channel the event originated in, which is the
+	// broadcaster of the event unless IsSharedChat is set.
+	Source Broadcaster
+	// SourceMessageId is the ID of the message in the source channel.
+	SourceMessageId string
+	// IsSharedChat is set when the event originated in another channel of
+	// a shared chat session.
+	IsSharedChat bool
+	// Session is the shared chat session of the broadcaster of the event,
+	// or empty when the broadcaster isn't known to be in one.
+	Session SharedChatSession
+}
+
+// SharedChatTracker keeps the shared chat session of each broadcaster and
+// tells which channel chat, notification and moderate events originated in.
+//
+// A bot in several channels of the same session receives a message once per
+// channel. The Filter methods wrap a client callback so it's only called for
+// the first copy that arrives within DedupWindow.
+type SharedChatTracker struct {
+	DedupWindow time.Duration
+
+	notifier
+
+	sessions map[string]SharedChatSession
+	updated  map[string]time.Time
+	seen     map[string]time.Time
+	pruned   time.Time
+	onChange func(broadcaster Broadcaster, session SharedChatSession)
+}
+
+func NewSharedChatTracker() *SharedChatTracker {
+	return &SharedChatTracker{
+		DedupWindow: DefaultSharedChatDedupWindow,
+		sessions:    map[string]SharedChatSession{},
+		updated:     map[string]time.Time{},
+		seen:        map[string]time.Time{},
+	}
+}
+
+// OnChange is called when a broadcaster joins, leaves or updates a shared
+// chat session. The session is empty when the broadcaster left.
+func (t *SharedChatTracker) OnChange(callback func(broadcaster Broadcaster, session SharedChatSession)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.onChange = callback
+}
+
+// Session returns the active shared chat session of the broadcaster.
+func (t *SharedChatTracker) Session(broadcasterID string) (SharedChatSession, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	session, ok := t.sessions[broadcasterID]
+	return session, ok
+}
+
+func (t *SharedChatTracker) HandleBegin(event EventChannelSharedChatBegin, msg NotificationMessage) {
+	t.update(event.Broadcaster, SharedChatSession{
+		HostBroadcaster: event.HostBroadcaster,
+		SessionId:       event.SessionId,
+		Participants:    event.Participants,
+	}, msg)
+}
+
+func (t *SharedChatTracker) HandleUpdate(event EventChannelSharedChatUpdate, msg NotificationMessage) {
+	t.HandleBegin(EventChannelSharedChatBegin(event), msg)
+}
+
+func (t *SharedChatTracker) HandleEnd(event EventChannelSharedChatEnd, msg NotificationMessage) {
+	t.update(event.Broadcaster, SharedChatSession{}, msg)
+}
+
+func (t *SharedChatTracker) MessageOrigin(event EventChannelChatMessage) SharedChatOrigin {
+	return t.origin(event.Broadcaster, event.SourceBroadcaster, event.SourceMessageId, false)
+}
+
+func (t *SharedChatTracker) NotificationOrigin(event EventChannelChatNotification) SharedChatOrigin {
+	return t.origin(event.Broadcaster, event.SourceBroadcaster, event.SourceMessageId, event.NoticeType.IsSharedChat())
+}
+
+func (t *SharedChatTracker) ModerateOrigin(event EventChannelModerate) SharedChatOrigin {
+	return t.origin(event.Broadcaster, event.SourceBroadcaster, "", event.IsSharedChat())
+}
+
+// FilterMessage wraps the callback so it's only called for the first copy
+// of a shared chat message.
+func (t *SharedChatTracker) FilterMessage(callback func(event EventChannelChatMessage, msg NotificationMessage)) func(event EventChannelChatMessage, msg NotificationMessage) {
+	return func(event EventChannelChatMessage, msg NotificationMessage) {
+		id := event.SourceMessageId
+		if id == "" {
+			id = event.MessageId
+		}
+		if t.first("message:"+id, msg.Metadata.MessageTimestamp) {
+			callback(event, msg)
+		}
+	}
+}
+
+// FilterNotification wraps the callback so it's only called for the first
+// copy of a shared chat notification.
+func (t *SharedChatTracker) FilterNotification(callback func(event EventChannelChatNotification, msg NotificationMessage)) func(event EventChannelChatNotification, msg NotificationMessage) {
+	return func(event EventChannelChatNotification, msg NotificationMessage) {
+		id := event.SourceMessageId
+		if id == "" {
+			id = event.MessageId
+		}
+		if t.first("notification:"+id, msg.Metadata.MessageTimestamp) {
+			callback(event, msg)
+		}
+	}
+}
+
+// FilterModerate wraps the callback so it's only called for the first copy
+// of an action taken in a shared chat session. Moderate events don't carry
+// an ID, so copies are matched by source channel, moderator, action and
+// target.
+func (t *SharedChatTracker) FilterModerate(callback func(event EventChannelModerate, msg NotificationMessage)) func(event EventChannelModerate, msg NotificationMessage) {
+	return func(event EventChannelModerate, msg NotificationMessage) {
+		origin := t.ModerateOrigin(event)
+		target, _ := event.TargetUser()
+		key := "moderate:" + origin.Source.BroadcasterUserId + ":" + event.ModeratorUserId + ":" + string(event.Action.Base()) + ":" + target.UserID
+		if detail, ok := event.Detail().(ModerateDelete); ok {
+			key += ":" + detail.MessageId
+		}
+		if t.first(key, msg.Metadata.MessageTimestamp) {
+			callback(event, msg)
+		}
+	}
+}
+
+func (t *SharedChatTracker) origin(broadcaster Broadcaster, source SourceBroadcaster, sourceMessageID string, isSharedChat bool) SharedChatOrigin {
+	origin := SharedChatOrigin{
+		Source:          broadcaster,
+		SourceMessageId: sourceMessageID,
+		IsSharedChat:    isSharedChat,
+	}
+	if source.SourceBroadcasterUserId != "" {
+		origin.Source = Broadcaster{
+			BroadcasterUserId:    source.SourceBroadcasterUserId,
+			BroadcasterUserLogin: source.SourceBroadcasterUserLogin,
+			BroadcasterUserName:  source.SourceBroadcasterUserName,
+		}
+		origin.IsSharedChat = isSharedChat || source.SourceBroadcasterUserId != broadcaster.BroadcasterUserId
+	}
+
+	t.mu.Lock()
+	origin.Session = t.sessions[broadcaster.BroadcasterUserId]
+	t.mu.Unlock()
+	return origin
+}
+
+// first reports if the key wasn't seen within DedupWindow of at.
+func (t *SharedChatTracker) first(key string, at time.Time) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if at.Sub(t.pruned) > t.DedupWindow {
+		for seenKey, seenAt := range t.seen {
+			if at.Sub(seenAt) > t.DedupWindow {
+				delete(t.seen, seenKey)
+			}
+		}
+		t.pruned = at
+	}
+
+	if seenAt, ok := t.seen[key]; ok {
+		gap := at.Sub(seenAt)
+		if gap < 0 {
+			gap = -gap
+		}
+		if gap <= t.DedupWindow {
+			return false
+		}
+	}
+	t.seen[key] = at
+	return true
+}
+
+func (t *SharedChatTracker) update(broadcaster Broadcaster, session SharedChatSession, msg NotificationMessage) {
+	at := msg.Metadata.MessageTimestamp
+
+	t.mu.Lock()
+	if isOutdated(t.updated, broadcaster.BroadcasterUserId, at) {
+		t.mu.Unlock()
+		return
+	}
+
+	if session.IsActive() {
+		t.sessions[broadcaster.BroadcasterUserId] = session
+	} else {
+		delete(t.sessions, broadcaster.BroadcasterUserId)
+	}
+
+	onChange := t.onChange
+	t.unlockAndNotify(func() {
+		if onChange != nil {
+			onChange(broadcaster, session)
+		}
+	})
+}
@@ -0,0 +1,122 @@
+package twitch_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/joeyak/go-twitch-eventsub/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSharedChatTrackerSession(t *testing.T) {
+	tracker := twitch.NewSharedChatTracker()
+
+	var sessions []twitch.SharedChatSession
+	tracker.OnChange(func(broadcaster twitch.Broadcaster, session twitch.SharedChatSession) {
+		sessions = append(sessions, session)
+	})
+
+	now := time.Now()
+	broadcaster := twitch.Broadcaster{BroadcasterUserId: "1337"}
+	begin := twitch.EventChannelSharedChatBegin{
+		Broadcaster:  broadcaster,
+		SessionId:    "session",
+		Participants: []twitch.Broadcaster{broadcaster, {BroadcasterUserId: "42"}},
+	}
+
+	tracker.HandleBegin(begin, newNotificationAt(now))
+	session, ok := tracker.Session("1337")
+	assert.True(t, ok)
+	assert.Equal(t, "session", session.SessionId)
+
+	tracker.HandleEnd(twitch.EventChannelSharedChatEnd{Broadcaster: broadcaster, SessionId: "session"}, newNotificationAt(now.Add(time.Minute)))
+	// a late update doesn't reopen the session
+	tracker.HandleUpdate(twitch.EventChannelSharedChatUpdate(begin), newNotificationAt(now.Add(time.Second)))
+
+	_, ok = tracker.Session("1337")
+	assert.False(t, ok)
+	assert.Len(t, sessions, 2)
+	assert.False(t, sessions[1].IsActive())
+}
+
+func TestSharedChatTrackerOrigin(t *testing.T) {
+	tracker := twitch.NewSharedChatTracker()
+
+	broadcaster := twitch.Broadcaster{BroadcasterUserId: "1337"}
+	tracker.HandleBegin(twitch.EventChannelSharedChatBegin{Broadcaster: broadcaster, SessionId: "session"}, newNotificationAt(time.Now()))
+
+	origin := tracker.MessageOrigin(twitch.EventChannelChatMessage{Broadcaster: broadcaster, MessageId: "a"})
+	assert.False(t, origin.IsSharedChat)
+	assert.Equal(t, broadcaster, origin.Source)
+	assert.Equal(t, "session", origin.Session.SessionId)
+
+	origin = tracker.MessageOrigin(twitch.EventChannelChatMessage{
+		Broadcaster:       broadcaster,
+		SourceBroadcaster: twitch.SourceBroadcaster{SourceBroadcasterUserId: "42", SourceBroadcasterUserLogin: "other"},
+		MessageId:         "b",
+		SourceMessageId:   "a",
+	})
+	assert.True(t, origin.IsSharedChat)
+	assert.Equal(t, twitch.Broadcaster{BroadcasterUserId: "42", BroadcasterUserLogin: "other"}, origin.Source)
+	assert.Equal(t, "a", origin.SourceMessageId)
+
+	origin = tracker.NotificationOrigin(twitch.EventChannelChatNotification{
+		Broadcaster:       broadcaster,
+		SourceBroadcaster: twitch.SourceBroadcaster{SourceBroadcasterUserId: "1337"},
+		NoticeType:        twitch.NoticeTypeSub,
+	})
+	assert.False(t, origin.IsSharedChat)
+
+	origin = tracker.ModerateOrigin(twitch.EventChannelModerate{
+		Broadcaster:       broadcaster,
+		SourceBroadcaster: twitch.SourceBroadcaster{SourceBroadcasterUserId: "42"},
+		Action:            twitch.ModerateActionSharedChatBan,
+	})
+	assert.True(t, origin.IsSharedChat)
+	assert.Equal(t, "42", origin.Source.BroadcasterUserId)
+}
+
+func TestSharedChatTrackerFilter(t *testing.T) {
+	tracker := twitch.NewSharedChatTracker()
+
+	var messages []string
+	handle := tracker.FilterMessage(func(event twitch.EventChannelChatMessage, msg twitch.NotificationMessage) {
+		messages = append(messages, event.MessageId)
+	})
+
+	now := time.Now()
+	source := twitch.SourceBroadcaster{SourceBroadcasterUserId: "42"}
+	handle(twitch.EventChannelChatMessage{Broadcaster: twitch.Broadcaster{BroadcasterUserId: "42"}, SourceBroadcaster: source, MessageId: "a", SourceMessageId: "a"}, newNotificationAt(now))
+	handle(twitch.EventChannelChatMessage{Broadcaster: twitch.Broadcaster{BroadcasterUserId: "1337"}, SourceBroadcaster: source, MessageId: "b", SourceMessageId: "a"}, newNotificationAt(now))
+	handle(twitch.EventChannelChatMessage{Broadcaster: twitch.Broadcaster{BroadcasterUserId: "1337"}, MessageId: "c"}, newNotificationAt(now))
+	assert.Equal(t, []string{"a", "c"}, messages)
+
+	var actions []twitch.ModerateAction
+	moderate := tracker.FilterModerate(func(event twitch.EventChannelModerate, msg twitch.NotificationMessage) {
+		actions = append(actions, event.Action)
+	})
+
+	ban := &twitch.Ban{User: twitch.User{UserID: "7"}}
+	moderate(twitch.EventChannelModerate{
+		Broadcaster:       twitch.Broadcaster{BroadcasterUserId: "1337"},
+		SourceBroadcaster: source,
+		Moderator:         twitch.Moderator{ModeratorUserId: "9"},
+		Action:            twitch.ModerateActionSharedChatBan,
+		SharedChatBan:     ban,
+	}, newNotificationAt(now))
+	moderate(twitch.EventChannelModerate{
+		Broadcaster:       twitch.Broadcaster{BroadcasterUserId: "42"},
+		SourceBroadcaster: source,
+		Moderator:         twitch.Moderator{ModeratorUserId: "9"},
+		Action:            twitch.ModerateActionBan,
+		Ban:               ban,
+	}, newNotificationAt(now.Add(time.Second)))
+	// the same action after the window is a new one
+	moderate(twitch.EventChannelModerate{
+		Broadcaster: twitch.Broadcaster{BroadcasterUserId: "42"},
+		Moderator:   twitch.Moderator{ModeratorUserId: "9"},
+		Action:      twitch.ModerateActionBan,
+		Ban:         ban,
+	}, newNotificationAt(now.Add(time.Minute)))
+	assert.Equal(t, []twitch.ModerateAction{twitch.ModerateActionSharedChatBan, twitch.ModerateActionBan}, actions)
+}
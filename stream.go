@@ -0,0 +1,299 @@
+package twitch
+
+import (
+	"time"
+)
+
+// StreamSegment is a part of a stream with the same channel information.
+type StreamSegment struct {
+	Title        string    `json:"title"`
+	Language     string    `json:"language"`
+	CategoryID   string    `json:"category_id"`
+	CategoryName string    `json:"category_name"`
+	StartedAt    time.Time `json:"started_at"`
+	EndedAt      time.Time `json:"ended_at"`
+}
+
+// Duration returns how long the segment lasted, or lasted until now if it
+// didn't end yet.
+func (s StreamSegment) Duration(now time.Time) time.Duration {
+	if !s.EndedAt.IsZero() {
+		now = s.EndedAt
+	}
+	return now.Sub(s.StartedAt)
+}
+
+type StreamRaid struct {
+	EventChannelRaid
+
+	// IsOutgoing is set when the broadcaster raided another channel.
+	IsOutgoing bool      `json:"is_outgoing"`
+	At         time.Time `json:"at"`
+}
+
+type StreamSession struct {
+	Broadcaster
+
+	ID         string                     `json:"id"`
+	Type       StreamType                 `json:"type"`
+	StartedAt  time.Time                  `json:"started_at"`
+	EndedAt    time.Time                  `json:"ended_at"`
+	Segments   []StreamSegment            `json:"segments"`
+	Raids      []StreamRaid               `json:"raids"`
+	AdBreaks   []EventChannelAdBreakBegin `json:"ad_breaks"`
+	HypeTrains []EventChannelHypeTrainEnd `json:"hype_trains"`
+}
+
+func (s StreamSession) IsLive() bool {
+	return s.EndedAt.IsZero()
+}
+
+// Duration returns how long the stream was live, or has been live until now.
+func (s StreamSession) Duration(now time.Time) time.Duration {
+	if !s.IsLive() {
+		now = s.EndedAt
+	}
+	return now.Sub(s.StartedAt)
+}
+
+func (s StreamSession) AdDuration() time.Duration {
+	var duration time.Duration
+	for _, adBreak := range s.AdBreaks {
+		duration += time.Duration(adBreak.DurationSeconds) * time.Second
+	}
+	return duration
+}
+
+// Segment returns the current or last segment of the stream.
+func (s StreamSession) Segment() (StreamSegment, bool) {
+	if len(s.Segments) == 0 {
+		return StreamSegment{}, false
+	}
+	return s.Segments[len(s.Segments)-1], true
+}
+
+// StreamTracker follows the stream sessions of each broadcaster. Channel
+// updates split a session into segments, and raids, ad breaks and hype
+// trains during a session are attached to it. Channel updates while offline
+// are remembered for the first segment of the next session.
+type StreamTracker struct {
+	notifier
+
+	sessions  map[string]StreamSession
+	channels  map[string]EventChannelUpdate
+	updatedAt map[string]time.Time
+	onOnline  func(session StreamSession)
+	onChange  func(session StreamSession)
+	onOffline func(session StreamSession)
+}
+
+func NewStreamTracker() *StreamTracker {
+	return &StreamTracker{
+		sessions:  map[string]StreamSession{},
+		channels:  map[string]EventChannelUpdate{},
+		updatedAt: map[string]time.Time{},
+	}
+}
+
+func (t *StreamTracker) OnOnline(callback func(session StreamSession)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.onOnline = callback
+}
+
+// OnChange is called when something is added to a live session.
+func (t *StreamTracker) OnChange(callback func(session StreamSession)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.onChange = callback
+}
+
+// OnOffline is called with the summary of the session when the stream
+// goes offline.
+func (t *StreamTracker) OnOffline(callback func(session StreamSession)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.onOffline = callback
+}
+
+// Session returns the current or last session of the broadcaster.
+func (t *StreamTracker) Session(broadcasterID string) (StreamSession, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	session, ok := t.sessions[broadcasterID]
+	return session, ok
+}
+
+func (t *StreamTracker) HandleOnline(event EventStreamOnline, msg NotificationMessage) {
+	startedAt := event.StartedAt
+	if startedAt.IsZero() {
+		startedAt = msg.Metadata.MessageTimestamp
+	}
+
+	t.mu.Lock()
+	old, ok := t.sessions[event.BroadcasterUserId]
+	if ok && (old.ID == event.Id || startedAt.Before(old.StartedAt)) {
+		t.mu.Unlock()
+		return
+	}
+
+	session := StreamSession{
+		Broadcaster: event.Broadcaster,
+		ID:          event.Id,
+		Type:        event.Type,
+		StartedAt:   startedAt,
+	}
+	if channel, ok := t.channels[event.BroadcasterUserId]; ok {
+		session.Segments = []StreamSegment{newStreamSegment(channel, startedAt)}
+	}
+	t.sessions[event.BroadcasterUserId] = session
+
+	// the offline of the old session was missed, so it ends when the new
+	// one starts
+	onOnline, onOffline := t.onOnline, t.onOffline
+	missedOffline := ok && old.IsLive()
+	if missedOffline {
+		old.EndedAt = startedAt
+		old.Segments = endSegment(old.Segments, startedAt)
+	}
+
+	t.unlockAndNotify(func() {
+		if missedOffline && onOffline != nil {
+			onOffline(old)
+		}
+		if onOnline != nil {
+			onOnline(session)
+		}
+	})
+}
+
+func (t *StreamTracker) HandleOffline(event EventStreamOffline, msg NotificationMessage) {
+	t.mu.Lock()
+	session, ok := t.sessions[event.BroadcasterUserId]
+	if !ok || !session.IsLive() || msg.Metadata.MessageTimestamp.Before(session.StartedAt) {
+		t.mu.Unlock()
+		return
+	}
+
+	session.EndedAt = msg.Metadata.MessageTimestamp
+	session.Segments = endSegment(session.Segments, session.EndedAt)
+	t.sessions[event.BroadcasterUserId] = session
+
+	t.notify(session, t.onOffline)
+}
+
+// HandleChannelUpdate starts a new segment when the title, language or
+// category changed during a session. Updates older than the last one are
+// ignored.
+func (t *StreamTracker) HandleChannelUpdate(event EventChannelUpdate, msg NotificationMessage) {
+	at := msg.Metadata.MessageTimestamp
+
+	t.mu.Lock()
+	if at.Before(t.updatedAt[event.BroadcasterUserId]) {
+		t.mu.Unlock()
+		return
+	}
+
+	session, ok := t.sessions[event.BroadcasterUserId]
+	last, hasSegment := session.Segment()
+	if ok && session.IsLive() && hasSegment && at.Before(last.StartedAt) {
+		t.mu.Unlock()
+		return
+	}
+
+	t.channels[event.BroadcasterUserId] = event
+	t.updatedAt[event.BroadcasterUserId] = at
+	if !ok || !session.IsLive() {
+		t.mu.Unlock()
+		return
+	}
+
+	segment := newStreamSegment(event, at)
+	if hasSegment && sameSegment(last, segment) {
+		t.mu.Unlock()
+		return
+	}
+
+	session.Segments = append(endSegment(session.Segments, at), segment)
+	t.sessions[event.BroadcasterUserId] = session
+
+	t.notify(session, t.onChange)
+}
+
+// HandleRaid attaches the raid to the session of the raiding and the raided
+// broadcaster.
+func (t *StreamTracker) HandleRaid(event EventChannelRaid, msg NotificationMessage) {
+	raid := StreamRaid{EventChannelRaid: event, At: msg.Metadata.MessageTimestamp}
+
+	raid.IsOutgoing = true
+	t.attach(event.FromBroadcasterUserId, msg, func(session *StreamSession) {
+		session.Raids = append(session.Raids, raid)
+	})
+
+	raid.IsOutgoing = false
+	t.attach(event.ToBroadcasterUserId, msg, func(session *StreamSession) {
+		session.Raids = append(session.Raids, raid)
+	})
+}
+
+func (t *StreamTracker) HandleAdBreakBegin(event EventChannelAdBreakBegin, msg NotificationMessage) {
+	t.attach(event.BroadcasterUserId, msg, func(session *StreamSession) {
+		session.AdBreaks = append(session.AdBreaks, event)
+	})
+}
+
+func (t *StreamTracker) HandleHypeTrainEnd(event EventChannelHypeTrainEnd, msg NotificationMessage) {
+	t.attach(event.BroadcasterUserId, msg, func(session *StreamSession) {
+		for _, train := range session.HypeTrains {
+			if train.Id == event.Id {
+				return
+			}
+		}
+		session.HypeTrains = append(session.HypeTrains, event)
+	})
+}
+
+func (t *StreamTracker) attach(broadcasterID string, msg NotificationMessage, add func(session *StreamSession)) {
+	t.mu.Lock()
+	session, ok := t.sessions[broadcasterID]
+	if !ok || !session.IsLive() || msg.Metadata.MessageTimestamp.Before(session.StartedAt) {
+		t.mu.Unlock()
+		return
+	}
+
+	add(&session)
+	t.sessions[broadcasterID] = session
+
+	t.notify(session, t.onChange)
+}
+
+func (t *StreamTracker) notify(session StreamSession, callback func(session StreamSession)) {
+	t.unlockAndNotify(func() {
+		if callback != nil {
+			callback(session)
+		}
+	})
+}
+
+func newStreamSegment(event EventChannelUpdate, startedAt time.Time) StreamSegment {
+	return StreamSegment{
+		Title:        event.Title,
+		Language:     event.Language,
+		CategoryID:   event.CategoryID,
+		CategoryName: event.CategoryName,
+		StartedAt:    startedAt,
+	}
+}
+
+func sameSegment(a, b StreamSegment) bool {
+	return a.Title == b.Title && a.Language == b.Language && a.CategoryID == b.CategoryID
+}
+
+// endSegment returns a copy of the segments with the last one ended.
+func endSegment(segments []StreamSegment, endedAt time.Time) []StreamSegment {
+	segments = append([]StreamSegment(nil), segments...)
+	if len(segments) > 0 && segments[len(segments)-1].EndedAt.IsZero() {
+		segments[len(segments)-1].EndedAt = endedAt
+	}
+	return segments
+}
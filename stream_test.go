@@ -0,0 +1,90 @@
+package twitch_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/joeyak/go-twitch-eventsub/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamTracker(t *testing.T) {
+	tracker := twitch.NewStreamTracker()
+
+	summaries := make(chan twitch.StreamSession, 1)
+	tracker.OnOffline(func(session twitch.StreamSession) { summaries <- session })
+
+	now := time.Now()
+	broadcaster := twitch.Broadcaster{BroadcasterUserId: "1337"}
+
+	tracker.HandleChannelUpdate(twitch.EventChannelUpdate{Broadcaster: broadcaster, Title: "Just Chatting", CategoryID: "509658"}, newNotificationAt(now.Add(-time.Hour)))
+	tracker.HandleOnline(twitch.EventStreamOnline{Broadcaster: broadcaster, Id: "9001", Type: twitch.StreamTypeLive, StartedAt: now}, newNotificationAt(now))
+
+	tracker.HandleChannelUpdate(twitch.EventChannelUpdate{Broadcaster: broadcaster, Title: "Speedrun", CategoryID: "1"}, newNotificationAt(now.Add(time.Hour)))
+	tracker.HandleChannelUpdate(twitch.EventChannelUpdate{Broadcaster: broadcaster, Title: "Speedrun", CategoryID: "1"}, newNotificationAt(now.Add(time.Hour+time.Minute)))
+	// an update delivered out of order doesn't split the segments
+	tracker.HandleChannelUpdate(twitch.EventChannelUpdate{Broadcaster: broadcaster, Title: "Old title", CategoryID: "2"}, newNotificationAt(now.Add(30*time.Minute)))
+	tracker.HandleAdBreakBegin(twitch.EventChannelAdBreakBegin{Broadcaster: broadcaster, DurationSeconds: 90}, newNotificationAt(now.Add(90*time.Minute)))
+	tracker.HandleHypeTrainEnd(twitch.EventChannelHypeTrainEnd{Broadcaster: broadcaster, Id: "train", Level: 3}, newNotificationAt(now.Add(100*time.Minute)))
+
+	raid := twitch.EventChannelRaid{Viewers: 50}
+	raid.FromBroadcasterUserId = "1337"
+	raid.ToBroadcasterUserId = "42"
+	tracker.HandleRaid(raid, newNotificationAt(now.Add(2*time.Hour)))
+
+	session, ok := tracker.Session("1337")
+	assert.True(t, ok)
+	assert.True(t, session.IsLive())
+	assert.Equal(t, 30*time.Minute, session.Duration(now.Add(30*time.Minute)))
+
+	tracker.HandleOffline(twitch.EventStreamOffline(broadcaster), newNotificationAt(now.Add(2*time.Hour)))
+
+	session = <-summaries
+	assert.False(t, session.IsLive())
+	assert.Equal(t, 2*time.Hour, session.Duration(now.Add(5*time.Hour)))
+	assert.Equal(t, 90*time.Second, session.AdDuration())
+	assert.Len(t, session.HypeTrains, 1)
+	assert.Len(t, session.Raids, 1)
+	assert.True(t, session.Raids[0].IsOutgoing)
+
+	assert.Len(t, session.Segments, 2)
+	assert.Equal(t, "Just Chatting", session.Segments[0].Title)
+	assert.Equal(t, time.Hour, session.Segments[0].Duration(now))
+	assert.Equal(t, "Speedrun", session.Segments[1].Title)
+	assert.Equal(t, now.Add(2*time.Hour), session.Segments[1].EndedAt)
+
+	// events after the stream ended are not attached
+	tracker.HandleAdBreakBegin(twitch.EventChannelAdBreakBegin{Broadcaster: broadcaster, DurationSeconds: 30}, newNotificationAt(now.Add(3*time.Hour)))
+	session, _ = tracker.Session("1337")
+	assert.Len(t, session.AdBreaks, 1)
+}
+
+func TestStreamTrackerMissedOffline(t *testing.T) {
+	tracker := twitch.NewStreamTracker()
+
+	var events []string
+	var summary twitch.StreamSession
+	tracker.OnOffline(func(session twitch.StreamSession) {
+		events = append(events, "offline "+session.ID)
+		summary = session
+	})
+	tracker.OnOnline(func(session twitch.StreamSession) { events = append(events, "online "+session.ID) })
+
+	now := time.Now()
+	broadcaster := twitch.Broadcaster{BroadcasterUserId: "1337"}
+
+	tracker.HandleChannelUpdate(twitch.EventChannelUpdate{Broadcaster: broadcaster, Title: "Just Chatting"}, newNotificationAt(now.Add(-time.Minute)))
+	tracker.HandleOnline(twitch.EventStreamOnline{Broadcaster: broadcaster, Id: "1", StartedAt: now}, newNotificationAt(now))
+	tracker.HandleOnline(twitch.EventStreamOnline{Broadcaster: broadcaster, Id: "2", StartedAt: now.Add(time.Hour)}, newNotificationAt(now.Add(time.Hour)))
+
+	assert.Equal(t, []string{"online 1", "offline 1", "online 2"}, events)
+	assert.False(t, summary.IsLive())
+	assert.Equal(t, now.Add(time.Hour), summary.EndedAt)
+	if assert.Len(t, summary.Segments, 1) {
+		assert.Equal(t, now.Add(time.Hour), summary.Segments[0].EndedAt)
+	}
+
+	session, _ := tracker.Session("1337")
+	assert.Equal(t, "2", session.ID)
+	assert.True(t, session.IsLive())
+}
@@ -5,9 +5,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"maps"
 	"net/http"
+	"strconv"
+	"time"
 )
 
 const twitchEventSubUrl = "https://api.twitch.tv/helix/eventsub/subscriptions"
@@ -427,6 +428,32 @@ type SubscribeRequest struct {
 	Condition map[string]string
 }
 
+type ResponseError struct {
+	StatusCode     int
+	Status         string
+	Body           string
+	RatelimitReset time.Time
+}
+
+func (e *ResponseError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Status, e.Body)
+}
+
+func newResponseError(resp *http.Response, body []byte) *ResponseError {
+	err := &ResponseError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Body:       string(body),
+	}
+
+	reset, parseErr := strconv.ParseInt(resp.Header.Get("Ratelimit-Reset"), 10, 64)
+	if parseErr == nil {
+		err.RatelimitReset = time.Unix(reset, 0)
+	}
+
+	return err
+}
+
 type SubscribeResponse struct {
 	Data         []PayloadSubscription `json:"data"`
 	Total        int                   `json:"total"`
@@ -434,6 +461,8 @@ type SubscribeResponse struct {
 	MaxTotalCost int                   `json:"max_total_cost"`
 }
 
+// SubscribeEvent sends the request once, without the budget or the rate
+// limiting of Client.Subscribe.
 func SubscribeEvent(request SubscribeRequest) (SubscribeResponse, error) {
 	return SubscribeEventUrlWithContext(context.Background(), request, twitchEventSubUrl)
 }
@@ -446,7 +475,15 @@ func SubscribeEventWithContext(ctx context.Context, request SubscribeRequest) (S
 	return SubscribeEventUrlWithContext(ctx, request, twitchEventSubUrl)
 }
 
+// SubscribeEventUrlWithContext sends the request once, without the budget
+// or the rate limiting of Client.Subscribe.
 func SubscribeEventUrlWithContext(ctx context.Context, request SubscribeRequest, url string) (SubscribeResponse, error) {
+	return subscribeEvent(ctx, request, url, nil, 0)
+}
+
+// subscribeEvent sends the request through the limiter, retrying it at most
+// maxRetries times, or once without rate limiting when limiter is nil.
+func subscribeEvent(ctx context.Context, request SubscribeRequest, url string, limiter *RateLimiter, maxRetries int) (SubscribeResponse, error) {
 	version := subMetadata[request.Event].Version
 	if request.VersionOverride != "" {
 		version = request.VersionOverride
@@ -475,16 +512,19 @@ func SubscribeEventUrlWithContext(ctx context.Context, request SubscribeRequest,
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", request.AccessToken))
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := http.DefaultClient.Do(req)
+	var resp *http.Response
+	var body []byte
+	if limiter == nil {
+		resp, body, err = send(req)
+	} else {
+		resp, body, err = limiter.do(ctx, req, false, maxRetries)
+	}
 	if err != nil {
 		return SubscribeResponse{}, fmt.Errorf("could not subscribe to event: %w", err)
 	}
-	defer resp.Body.Close()
-
-	body, _ := io.ReadAll(resp.Body)
 
 	if resp.StatusCode != 202 {
-		return SubscribeResponse{}, fmt.Errorf("could not subscribe to event: %s: %s", resp.Status, string(body))
+		return SubscribeResponse{}, fmt.Errorf("could not subscribe to event: %w", newResponseError(resp, body))
 	}
 
 	var subscription SubscribeResponse
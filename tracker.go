@@ -0,0 +1,43 @@
+package twitch
+
+import (
+	"sync"
+	"time"
+)
+
+// notifier holds the locks of the trackers. mu guards the tracker's state,
+// callbacks are called without it so they can read the tracker, while
+// notifyMu keeps them in the order the states were stored.
+type notifier struct {
+	mu       sync.Mutex
+	notifyMu sync.Mutex
+}
+
+// unlockAndNotify must be called with mu held. It releases mu and calls
+// notify, which should only use values copied while mu was held.
+func (n *notifier) unlockAndNotify(notify func()) {
+	n.notifyMu.Lock()
+	n.mu.Unlock()
+	defer n.notifyMu.Unlock()
+
+	notify()
+}
+
+// isStale reports whether an update should be dropped. A completing update
+// always wins over an open one, otherwise the newer message wins.
+func isStale(old, new time.Time, oldComplete, newComplete bool) bool {
+	if oldComplete != newComplete {
+		return oldComplete
+	}
+	return new.Before(old)
+}
+
+// isOutdated reports whether at is older than the last update of key,
+// storing at as the last update otherwise.
+func isOutdated[K comparable](updated map[K]time.Time, key K, at time.Time) bool {
+	if at.Before(updated[key]) {
+		return true
+	}
+	updated[key] = at
+	return false
+}
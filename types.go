@@ -31,6 +31,26 @@ type SubscriptionRequest struct {
 	Transport SubscriptionTransport `json:"transport"`
 }
 
+type SubscriptionStatus string
+
+const (
+	SubscriptionStatusEnabled                      SubscriptionStatus = "enabled"
+	SubscriptionStatusAuthorizationRevoked         SubscriptionStatus = "authorization_revoked"
+	SubscriptionStatusModeratorRemoved             SubscriptionStatus = "moderator_removed"
+	SubscriptionStatusUserRemoved                  SubscriptionStatus = "user_removed"
+	SubscriptionStatusVersionRemoved               SubscriptionStatus = "version_removed"
+	SubscriptionStatusNotificationFailuresExceeded SubscriptionStatus = "notification_failures_exceeded"
+	SubscriptionStatusBetaMaintenance              SubscriptionStatus = "beta_maintenance"
+	SubscriptionStatusWebsocketDisconnected        SubscriptionStatus = "websocket_disconnected"
+	SubscriptionStatusWebsocketFailedPingPong      SubscriptionStatus = "websocket_failed_ping_pong"
+	SubscriptionStatusWebsocketReceivedInbound     SubscriptionStatus = "websocket_received_inbound_traffic"
+	SubscriptionStatusWebsocketConnectionUnused    SubscriptionStatus = "websocket_connection_unused"
+	SubscriptionStatusWebsocketInternalError       SubscriptionStatus = "websocket_internal_error"
+	SubscriptionStatusWebsocketNetworkTimeout      SubscriptionStatus = "websocket_network_timeout"
+	SubscriptionStatusWebsocketNetworkError        SubscriptionStatus = "websocket_network_error"
+	SubscriptionStatusWebsocketFailedToReconnect   SubscriptionStatus = "websocket_failed_to_reconnect"
+)
+
 type PayloadSubscription struct {
 	SubscriptionRequest
 
@@ -73,3 +93,14 @@ type RevokeMessage struct {
 		Subscription PayloadSubscription `json:"subscription"`
 	} `json:"payload"`
 }
+
+// Request rebuilds the subscribe request of the revoked subscription with
+// the version it was created with. Clear VersionOverride to resubscribe
+// with the library's current version after a version_removed revocation.
+func (m RevokeMessage) Request() SubscribeRequest {
+	return SubscribeRequest{
+		VersionOverride: m.Payload.Subscription.Version,
+		Event:           m.Payload.Subscription.Type,
+		Condition:       m.Payload.Subscription.Condition,
+	}
+}
@@ -0,0 +1,371 @@
+package twitch
+
+import (
+	"sort"
+	"time"
+)
+
+// DefaultVoteRetention is how long a VoteTracker keeps completed polls and
+// predictions by default.
+const DefaultVoteRetention = 10 * time.Minute
+
+// VoteState is a PollState or PredictionState passed to the VoteTracker
+// change callback.
+type VoteState interface {
+	StateID() string
+	IsComplete() bool
+}
+
+type PollState struct {
+	Broadcaster
+
+	ID                  string       `json:"id"`
+	Title               string       `json:"title"`
+	Choices             []PollChoice `json:"choices"`
+	BitsVoting          PollVoting   `json:"bits_voting"`
+	ChannelPointsVoting PollVoting   `json:"channel_points_voting"`
+	Status              PollStatus   `json:"status"`
+	StartedAt           time.Time    `json:"started_at"`
+	EndsAt              time.Time    `json:"ends_at"`
+	UpdatedAt           time.Time    `json:"updated_at"`
+}
+
+func (s PollState) StateID() string { return s.ID }
+
+func (s PollState) IsComplete() bool {
+	return s.Status != "" && s.Status != PollStatusActive
+}
+
+func (s PollState) TotalVotes() int {
+	total := 0
+	for _, choice := range s.Choices {
+		total += choice.Votes
+	}
+	return total
+}
+
+// Shares returns the share of votes of each choice from 0 to 1, in the same
+// order as Choices.
+func (s PollState) Shares() []float64 {
+	total := s.TotalVotes()
+	shares := make([]float64, len(s.Choices))
+	for i, choice := range s.Choices {
+		if total > 0 {
+			shares[i] = float64(choice.Votes) / float64(total)
+		}
+	}
+	return shares
+}
+
+// Winner returns the choice with the most votes so far. There's no winner
+// without votes or when the lead is tied.
+func (s PollState) Winner() (PollChoice, bool) {
+	var winner PollChoice
+	tied := false
+	for _, choice := range s.Choices {
+		switch {
+		case choice.Votes > winner.Votes:
+			winner, tied = choice, false
+		case choice.Votes == winner.Votes:
+			tied = true
+		}
+	}
+	return winner, winner.Votes > 0 && !tied
+}
+
+type PredictionState struct {
+	Broadcaster
+
+	ID               string              `json:"id"`
+	Title            string              `json:"title"`
+	Outcomes         []PredictionOutcome `json:"outcomes"`
+	WinningOutcomeID string              `json:"winning_outcome_id"`
+	Status           PredictionStatus    `json:"status"`
+	StartedAt        time.Time           `json:"started_at"`
+	LocksAt          time.Time           `json:"locks_at"`
+	EndedAt          time.Time           `json:"ended_at"`
+	UpdatedAt        time.Time           `json:"updated_at"`
+}
+
+func (s PredictionState) StateID() string { return s.ID }
+
+func (s PredictionState) IsComplete() bool {
+	return s.Status == PredictionStatusResolved || s.Status == PredictionStatusCanceled
+}
+
+func (s PredictionState) TotalChannelPoints() int {
+	total := 0
+	for _, outcome := range s.Outcomes {
+		total += outcome.ChannelPoints
+	}
+	return total
+}
+
+// Shares returns the share of channel points of each outcome from 0 to 1, in
+// the same order as Outcomes.
+func (s PredictionState) Shares() []float64 {
+	total := s.TotalChannelPoints()
+	shares := make([]float64, len(s.Outcomes))
+	for i, outcome := range s.Outcomes {
+		if total > 0 {
+			shares[i] = float64(outcome.ChannelPoints) / float64(total)
+		}
+	}
+	return shares
+}
+
+// Winner returns the winning outcome once the prediction is resolved.
+func (s PredictionState) Winner() (PredictionOutcome, bool) {
+	if s.WinningOutcomeID == "" {
+		return PredictionOutcome{}, false
+	}
+
+	for _, outcome := range s.Outcomes {
+		if outcome.ID == s.WinningOutcomeID {
+			return outcome, true
+		}
+	}
+	return PredictionOutcome{}, false
+}
+
+// TopPredictors returns up to n predictors of all outcomes, ordered by the
+// channel points won and then by the channel points used. A negative n
+// returns all of them.
+func (s PredictionState) TopPredictors(n int) []TopPredictor {
+	var predictors []TopPredictor
+	for _, outcome := range s.Outcomes {
+		predictors = append(predictors, outcome.TopPredictors...)
+	}
+
+	won := func(p TopPredictor) int {
+		if p.ChannelPointsWon == nil {
+			return 0
+		}
+		return *p.ChannelPointsWon
+	}
+	sort.SliceStable(predictors, func(i, j int) bool {
+		if won(predictors[i]) != won(predictors[j]) {
+			return won(predictors[i]) > won(predictors[j])
+		}
+		return predictors[i].ChannelPointsUsed > predictors[j].ChannelPointsUsed
+	})
+
+	if n >= 0 && len(predictors) > n {
+		predictors = predictors[:n]
+	}
+	return predictors
+}
+
+// VoteTracker keeps the current state of polls and predictions. Events are
+// dispatched concurrently, so updates older than the stored state are
+// dropped by their message timestamp, and nothing but the end event is
+// applied to a completed poll or prediction.
+//
+// Wire the handlers to a client like
+//
+//	client.OnEventChannelPollProgress(tracker.HandlePollProgress)
+type VoteTracker struct {
+	notifier
+
+	// Retention is how long a completed poll or prediction is kept after
+	// its last update, measured by the timestamps of later messages. Zero
+	// keeps them until they are forgotten.
+	Retention time.Duration
+
+	polls       map[string]PollState
+	predictions map[string]PredictionState
+	onChange    func(state VoteState)
+}
+
+func NewVoteTracker() *VoteTracker {
+	return &VoteTracker{
+		Retention:   DefaultVoteRetention,
+		polls:       map[string]PollState{},
+		predictions: map[string]PredictionState{},
+	}
+}
+
+// OnChange is called with the new state every time a poll or prediction
+// changes.
+func (t *VoteTracker) OnChange(callback func(state VoteState)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.onChange = callback
+}
+
+func (t *VoteTracker) Poll(id string) (PollState, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	state, ok := t.polls[id]
+	return state, ok
+}
+
+func (t *VoteTracker) Prediction(id string) (PredictionState, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	state, ok := t.predictions[id]
+	return state, ok
+}
+
+// Polls returns the polls that are not complete, oldest first.
+func (t *VoteTracker) Polls() []PollState {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var polls []PollState
+	for _, state := range t.polls {
+		if !state.IsComplete() {
+			polls = append(polls, state)
+		}
+	}
+	sort.Slice(polls, func(i, j int) bool { return polls[i].StartedAt.Before(polls[j].StartedAt) })
+	return polls
+}
+
+// Predictions returns the predictions that are not complete, oldest first.
+func (t *VoteTracker) Predictions() []PredictionState {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var predictions []PredictionState
+	for _, state := range t.predictions {
+		if !state.IsComplete() {
+			predictions = append(predictions, state)
+		}
+	}
+	sort.Slice(predictions, func(i, j int) bool { return predictions[i].StartedAt.Before(predictions[j].StartedAt) })
+	return predictions
+}
+
+// Forget drops a completed poll or prediction before its Retention is over.
+// A late event for it would be tracked again, so only forget states that
+// ended a while ago.
+func (t *VoteTracker) Forget(id string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.polls, id)
+	delete(t.predictions, id)
+}
+
+func (t *VoteTracker) HandlePollBegin(event EventChannelPollBegin, msg NotificationMessage) {
+	t.updatePoll(event, PollStatusActive, msg)
+}
+
+func (t *VoteTracker) HandlePollProgress(event EventChannelPollProgress, msg NotificationMessage) {
+	t.updatePoll(EventChannelPollBegin(event), PollStatusActive, msg)
+}
+
+func (t *VoteTracker) HandlePollEnd(event EventChannelPollEnd, msg NotificationMessage) {
+	t.updatePoll(event.EventChannelPollBegin, event.Status, msg)
+}
+
+func (t *VoteTracker) HandlePredictionBegin(event EventChannelPredictionBegin, msg NotificationMessage) {
+	t.updatePrediction(PredictionState{
+		Broadcaster: event.Broadcaster,
+		ID:          event.ID,
+		Title:       event.Title,
+		Outcomes:    event.Outcomes,
+		Status:      PredictionStatusActive,
+		StartedAt:   event.StartedAt,
+		LocksAt:     event.LocksAt,
+	}, msg)
+}
+
+func (t *VoteTracker) HandlePredictionProgress(event EventChannelPredictionProgress, msg NotificationMessage) {
+	t.HandlePredictionBegin(EventChannelPredictionBegin(event), msg)
+}
+
+func (t *VoteTracker) HandlePredictionLock(event EventChannelPredictionLock, msg NotificationMessage) {
+	t.updatePrediction(PredictionState{
+		Broadcaster: event.Broadcaster,
+		ID:          event.ID,
+		Title:       event.Title,
+		Outcomes:    event.Outcomes,
+		Status:      PredictionStatusLocked,
+		StartedAt:   event.StartedAt,
+		LocksAt:     event.LocksAt,
+	}, msg)
+}
+
+func (t *VoteTracker) HandlePredictionEnd(event EventChannelPredictionEnd, msg NotificationMessage) {
+	t.updatePrediction(PredictionState{
+		Broadcaster:      event.Broadcaster,
+		ID:               event.ID,
+		Title:            event.Title,
+		Outcomes:         event.Outcomes,
+		WinningOutcomeID: event.WinningOutcomeID,
+		Status:           event.Status,
+		StartedAt:        event.StartedAt,
+		EndedAt:          event.EndedAt,
+	}, msg)
+}
+
+func (t *VoteTracker) updatePoll(event EventChannelPollBegin, status PollStatus, msg NotificationMessage) {
+	state := PollState{
+		Broadcaster:         event.Broadcaster,
+		ID:                  event.ID,
+		Title:               event.Title,
+		Choices:             event.Choices,
+		BitsVoting:          event.BitsVoting,
+		ChannelPointsVoting: event.ChannelPointsVoting,
+		Status:              status,
+		StartedAt:           event.StartedAt,
+		EndsAt:              event.EndsAt,
+		UpdatedAt:           msg.Metadata.MessageTimestamp,
+	}
+
+	t.mu.Lock()
+	t.evict(state.UpdatedAt)
+	old, ok := t.polls[state.ID]
+	if ok && isStale(old.UpdatedAt, state.UpdatedAt, old.IsComplete(), state.IsComplete()) {
+		t.mu.Unlock()
+		return
+	}
+	t.polls[state.ID] = state
+	t.notify(state)
+}
+
+func (t *VoteTracker) updatePrediction(state PredictionState, msg NotificationMessage) {
+	state.UpdatedAt = msg.Metadata.MessageTimestamp
+
+	t.mu.Lock()
+	t.evict(state.UpdatedAt)
+	old, ok := t.predictions[state.ID]
+	if ok && isStale(old.UpdatedAt, state.UpdatedAt, old.IsComplete(), state.IsComplete()) {
+		t.mu.Unlock()
+		return
+	}
+	if state.LocksAt.IsZero() {
+		state.LocksAt = old.LocksAt
+	}
+	t.predictions[state.ID] = state
+	t.notify(state)
+}
+
+// evict drops the polls and predictions that completed more than Retention
+// before now. It must be called with mu held.
+func (t *VoteTracker) evict(now time.Time) {
+	if t.Retention <= 0 {
+		return
+	}
+
+	for id, state := range t.polls {
+		if state.IsComplete() && now.Sub(state.UpdatedAt) > t.Retention {
+			delete(t.polls, id)
+		}
+	}
+	for id, state := range t.predictions {
+		if state.IsComplete() && now.Sub(state.UpdatedAt) > t.Retention {
+			delete(t.predictions, id)
+		}
+	}
+}
+
+func (t *VoteTracker) notify(state VoteState) {
+	onChange := t.onChange
+	t.unlockAndNotify(func() {
+		if onChange != nil {
+			onChange(state)
+		}
+	})
+}
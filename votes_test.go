@@ -0,0 +1,130 @@
+package twitch_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/joeyak/go-twitch-eventsub/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVoteTrackerPoll(t *testing.T) {
+	tracker := twitch.NewVoteTracker()
+
+	var states []twitch.VoteState
+	tracker.OnChange(func(state twitch.VoteState) { states = append(states, state) })
+
+	now := time.Now()
+	poll := twitch.EventChannelPollBegin{
+		ID: "poll",
+		Choices: []twitch.PollChoice{
+			{ID: "a", Votes: 0},
+			{ID: "b", Votes: 0},
+		},
+	}
+	tracker.HandlePollBegin(poll, newNotificationAt(now))
+
+	poll.Choices[1].Votes = 3
+	poll.Choices[0].Votes = 1
+	tracker.HandlePollEnd(twitch.EventChannelPollEnd{EventChannelPollBegin: poll, Status: twitch.PollStatusCompleted}, newNotificationAt(now.Add(2*time.Second)))
+
+	late := twitch.EventChannelPollBegin{ID: "poll", Choices: []twitch.PollChoice{{ID: "a", Votes: 1}, {ID: "b", Votes: 1}}}
+	tracker.HandlePollProgress(twitch.EventChannelPollProgress(late), newNotificationAt(now.Add(3*time.Second)))
+
+	assert.Len(t, states, 2)
+
+	state, ok := tracker.Poll("poll")
+	assert.True(t, ok)
+	assert.True(t, state.IsComplete())
+	assert.Equal(t, 4, state.TotalVotes())
+	assert.Equal(t, []float64{0.25, 0.75}, state.Shares())
+
+	winner, ok := state.Winner()
+	assert.True(t, ok)
+	assert.Equal(t, "b", winner.ID)
+	assert.Empty(t, tracker.Polls())
+}
+
+func TestVoteTrackerPollOutOfOrder(t *testing.T) {
+	tracker := twitch.NewVoteTracker()
+
+	now := time.Now()
+	newer := twitch.EventChannelPollProgress{ID: "poll", Choices: []twitch.PollChoice{{ID: "a", Votes: 5}, {ID: "b", Votes: 5}}}
+	older := twitch.EventChannelPollProgress{ID: "poll", Choices: []twitch.PollChoice{{ID: "a", Votes: 1}, {ID: "b", Votes: 0}}}
+	tracker.HandlePollProgress(newer, newNotificationAt(now.Add(time.Second)))
+	tracker.HandlePollProgress(older, newNotificationAt(now))
+
+	state, _ := tracker.Poll("poll")
+	assert.Equal(t, 10, state.TotalVotes())
+	_, ok := state.Winner()
+	assert.False(t, ok)
+	assert.Len(t, tracker.Polls(), 1)
+}
+
+func TestVoteTrackerPrediction(t *testing.T) {
+	tracker := twitch.NewVoteTracker()
+
+	var last twitch.VoteState
+	tracker.OnChange(func(state twitch.VoteState) { last = state })
+
+	won := 300
+	now := time.Now()
+	locksAt := now.Add(time.Minute)
+	tracker.HandlePredictionBegin(twitch.EventChannelPredictionBegin{ID: "prediction", LocksAt: locksAt}, newNotificationAt(now))
+	tracker.HandlePredictionEnd(twitch.EventChannelPredictionEnd{
+		ID:               "prediction",
+		WinningOutcomeID: "yes",
+		Status:           twitch.PredictionStatusResolved,
+		Outcomes: []twitch.PredictionOutcome{
+			{ID: "yes", ChannelPoints: 100, TopPredictors: []twitch.TopPredictor{
+				{User: twitch.User{UserLogin: "winner"}, ChannelPointsUsed: 100, ChannelPointsWon: &won},
+			}},
+			{ID: "no", ChannelPoints: 300, TopPredictors: []twitch.TopPredictor{
+				{User: twitch.User{UserLogin: "big"}, ChannelPointsUsed: 200},
+				{User: twitch.User{UserLogin: "small"}, ChannelPointsUsed: 100},
+			}},
+		},
+	}, newNotificationAt(now.Add(time.Second)))
+	tracker.HandlePredictionLock(twitch.EventChannelPredictionLock{ID: "prediction"}, newNotificationAt(now.Add(2*time.Second)))
+
+	state, ok := last.(twitch.PredictionState)
+	assert.True(t, ok)
+	assert.True(t, state.IsComplete())
+	assert.Equal(t, locksAt, state.LocksAt)
+	assert.Equal(t, []float64{0.25, 0.75}, state.Shares())
+
+	winner, ok := state.Winner()
+	assert.True(t, ok)
+	assert.Equal(t, "yes", winner.ID)
+
+	top := state.TopPredictors(2)
+	assert.Len(t, top, 2)
+	assert.Equal(t, "winner", top[0].UserLogin)
+	assert.Equal(t, "big", top[1].UserLogin)
+}
+
+func TestVoteTrackerRetention(t *testing.T) {
+	tracker := twitch.NewVoteTracker()
+	tracker.Retention = time.Minute
+
+	now := time.Now()
+	tracker.HandlePollEnd(twitch.EventChannelPollEnd{
+		EventChannelPollBegin: twitch.EventChannelPollBegin{ID: "ended"},
+		Status:                twitch.PollStatusCompleted,
+	}, newNotificationAt(now))
+	tracker.HandlePredictionEnd(twitch.EventChannelPredictionEnd{ID: "resolved", Status: twitch.PredictionStatusResolved}, newNotificationAt(now))
+	tracker.HandlePollBegin(twitch.EventChannelPollBegin{ID: "active"}, newNotificationAt(now))
+
+	// still kept within the retention
+	tracker.HandlePollProgress(twitch.EventChannelPollProgress{ID: "active"}, newNotificationAt(now.Add(30*time.Second)))
+	_, ok := tracker.Poll("ended")
+	assert.True(t, ok)
+
+	tracker.HandlePredictionBegin(twitch.EventChannelPredictionBegin{ID: "next"}, newNotificationAt(now.Add(2*time.Minute)))
+	_, ok = tracker.Poll("ended")
+	assert.False(t, ok, "completed poll was not evicted")
+	_, ok = tracker.Prediction("resolved")
+	assert.False(t, ok, "completed prediction was not evicted")
+	_, ok = tracker.Poll("active")
+	assert.True(t, ok, "active poll was evicted")
+}